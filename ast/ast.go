@@ -1,6 +1,7 @@
 package ast
 
 import (
+	"vhdl/literal"
 	"vhdl/token"
 )
 
@@ -16,6 +17,41 @@ type Comment struct {
 	Text  string    // comment text (excluding '\n' for //-style comments)
 }
 
+// Pragma is a synthesis metacomment found in the source
+type Pragma struct {
+	literal.Pragma
+	Node
+}
+
+// ProtectedEnvelope is a `protect begin_protected ... `protect end_protected region
+// of encrypted IP (LRM 24.1), its content is not analyzed
+type ProtectedEnvelope struct {
+	Directives []ProtectDirective
+	Blocks     []ProtectBlock
+	Node
+}
+
+// ProtectDirective is a `protect tool directive
+type ProtectDirective struct {
+	Keyword string // first word after `protect in lower case, e.g. begin_protected, data_method
+	Text    string // whole directive
+	Node
+}
+
+// ProtectBlock holds the encoded lines that follow a key_block, data_block or digest_block directive
+type ProtectBlock struct {
+	Keyword string
+	Data    string
+	Node
+}
+
+// PragmaRegion is the text between an off pragma and the matching on pragma,
+// On is token.NoPos if the region extends to the end of the file
+type PragmaRegion struct {
+	Off token.Pos
+	On  token.Pos
+}
+
 //We are following the VHDL 2019 LRM
 //https://standards.ieee.org/standard/1076-2019.html
 
@@ -50,10 +86,16 @@ type EntityHeader struct {
 	Node
 }
 
+// FormalGenericClause is generic ( generic_list ) ;
 type FormalGenericClause struct {
+	GenericList InterfaceList
+	Node
 }
 
+// FormalPortClause is port ( port_list ) ;
 type FormalPortClause struct {
+	PortList InterfaceList
+	Node
 }
 
 type EntityDeclarativePart struct {
@@ -138,74 +180,15 @@ type ComponentConfiguration struct {
 
 /*
 // 4 Subprogram and package
-type SubprogramDeclaration struct {
-	SubprogramSpecification SubprogramSpecification
-}
-
-type SubprogramSpecification interface{}
-
-type ProcedureSpecification struct {
-	Designator          Designator
-	SubprogramHeader    SubprogramHeader
-	FormalParameterList *FormalParameterList
-}
-
-type FunctionSpecification struct {
-	Designator          Designator
-	SubprogramHeader    SubprogramHeader
-	FormalParameterList *FormalParameterList
-	ReturnIdentifier    ReturnIdentifier
-	TypeMark            TypeMark
-}
-
 type SubprogramHeader struct {
 	GenericList      *GenericList
 	GenericMapAspect *GenericMapAspect
 }
 
-type Designator interface {
-	OptionalNode
-}
-
 type OperatorSymbol struct {
 	StringLiteral
 }
 
-type FormalParameterList struct {
-	ParameterInterfaceList
-}
-
-type SubprogramBody struct {
-	SubprogramSpecification   SubprogramSpecification
-	SubprogramDeclarativePart SubprogramDeclarativePart
-	SubprogramStatementPart   SubprogramStatementPart
-	SubprogramKind            *SubprogramKind
-	Designator                *Designator
-}
-
-type SubprogramDeclarativePart struct {
-	SubprogramDeclarativeItems *[]SubprogramDeclarativeItem
-}
-
-type SubprogramDeclarativeItem interface {
-	OptionalNode
-}
-
-type SubprogramStatementPart struct {
-	SequentialStatements *[]SequentialStatement
-}
-
-type SubprogramKind struct {
-	Node
-	token token.Token
-}
-
-type Signature struct {
-	TypeMark       *TypeMark
-	TypeMarkList   *[]TypeMark
-	ReturnTypeMark *TypeMark
-}
-
 type PackageBody struct {
 	PackageSimpleName          PackageSimpleName
 	PackageBodyDeclarativePart PackageBodyDeclarativePart
@@ -226,110 +209,15 @@ type PackageInstantiationDeclaration struct {
 
 // 5 Types
 
-type ScalarTypeDefinition interface{}
-
-type RangeConstraint struct {
-	Range Range
-}
-
-type Range interface{}
-
-type SimpleRange struct {
-	SimpleExpression  SimpleExpression
-	Direction         Direction
-	SimpleExpression2 SimpleExpression
-}
-
-type Direction struct {
-	Node
-	token token.Token
-}
-
-type EnumerationTypeDefinition struct {
-	EnumerationLiteral     EnumerationLiteral
-	EnumerationLiteralList *[]EnumerationLiteral
-}
-
-type EnumerationLiteral interface{}
-
-type IntegerTypeDefinition struct {
-	RangeConstraint
-}
-
-type PhysicalTypeDefinition struct {
-	RangeConstraint          RangeConstraint
-	PrimaryUnitDeclaration   PrimaryUnitDeclaration
-	SecondaryUnitDeclaration *[]SecondaryUnitDeclaration
-	PhysicalTypeSimpleName   *PhysicalTypeSimpleName
-}
-
-type PrimaryUnitDeclaration struct {
-	Identifier
-}
-
-type SecondaryUnitDeclaration struct {
-	Identifier      Identifier
-	PhysicalLiteral PhysicalLiteral
-}
-
 type PhysicalLiteral struct {
 	AbstractLiteral *AbstractLiteral
 	UnitName        UnitName
 }
 
-type FloatingTypeDefinition struct {
-	RangeConstraint RangeConstraint
-}
-
-type CompositeTypeDefinition interface{}
-
-type ArrayTypeDefinition interface{}
-
-type UnboundedArrayDefinition struct {
-	IndexSubtypeDefinition     IndexSubtypeDefinition
-	IndexSubtypeDefinitionList *[]IndexSubtypeDefinition
-	ElementSubtypeIndication   ElementSubtypeIndication
-}
-
-type ConstrainedArrayDefinition struct {
-	IndexConstraint          IndexConstraint
-	ElementSubtypeIndication ElementSubtypeIndication
-}
-
-type IndexSubtypeDefinition struct {
-	TypeMark TypeMark
-}
-
 type ArrayConstraint interface {
 	OptionalNode
 }
 
-type IndexConstraint struct {
-	DiscreteRange     DiscreteRange
-	DiscreteRangeList *[]DiscreteRange
-}
-
-type DiscreteRange interface{}
-
-type RecordTypeDefinition struct {
-	ElementDeclarations  *[]ElementDeclaration
-	RecordTypeSimpleName *RecordTypeSimpleName
-}
-
-type ElementDeclaration struct {
-	IdentifierList           IdentifierList
-	ElementSubtypeDefinition ElementSubtypeDefinition
-}
-
-type IdentifierList struct {
-	Identifier
-	IdentifierList *[]Identifier
-}
-
-type ElementSubtypeDefinition struct {
-	SubtypeIndication
-}
-
 type RecordConstraint struct {
 	RecordElementConstraint     RecordElementConstraint
 	RecordElementConstraintList *[]RecordElementConstraint
@@ -340,54 +228,15 @@ type RecordElementConstraint struct {
 	ElementConstraint       ElementConstraint
 }
 
-type AccessTypeDefinition struct {
-	SubtypeIndication SubtypeIndication
-	GenericMapAspect  *GenericMapAspect
-}
-
-type ImcompleteTypeDeclaration struct {
-	Identifier Identifier
-}
-
-type FileTypeDefinition struct {
-	TypeMark TypeMark
-}
-
-type ProtectedTypeDefinition interface{}
-
-type ProtectedTypeDeclaration struct {
-	ProtectedTypeHeader          ProtectedTypeHeader
-	ProtectedTypeDeclarativePart ProtectedTypeDeclarativePart
-	ProtectedTypeSimpleName      *ProtectedTypeSimpleName
-}
-
 type ProtectedTypeHeader struct {
 	GenericClause    *GenericClause
 	GenericMapAspect *GenericMapAspect
 }
 
-type ProtectedTypeDeclarativePart struct {
-	ProtectedTypeDeclarativeItem  ProtectedTypeDeclarativeItem
-	ProtectedTypeDeclarativeItems *[]ProtectedTypeDeclarativeItem
-}
-
-type ProtectedTypeDeclarativeItem interface{}
-
 type PrivateVariableDeclaration struct {
 	VariableDeclaration VariableDeclaration
 }
 
-type ProtectedTypeBody struct {
-	ProtectedTypeBodyDeclarativePart ProtectedTypeBodyDeclarativePart
-	ProtectedTypeSimpleName          *ProtectedTypeSimpleName
-}
-
-type ProtectedTypeBodyDeclarativePart struct {
-	ProtectedTypeBodyDeclarativeItems *[]ProtectedTypeBodyDeclarativeItem
-}
-
-type ProtectedTypeBodyDeclarativeItem interface{}
-
 type ProtectedTypeInstantiationDefinition struct {
 	SubtypeIndication SubtypeIndication
 	GenericMapAspect  *GenericMapAspect
@@ -454,30 +303,6 @@ type FileIncompleteTypeDefinition struct {
 
 //6 Declarations and types
 
-type TypeDeclaration interface{}
-
-type FullTypeDeclaration struct {
-	Identifier     Identifier
-	TypeDefinition TypeDefinition
-}
-
-type TypeDefinition interface{}
-
-type SubtypeDeclaration struct {
-	Identifier        Identifier
-	SubtypeIndication SubtypeIndication
-}
-
-type SubtypeIndication struct {
-	ResolutionIndication *ResolutionIndication
-	TypeMark             TypeMark
-	Constraint           *Constraint
-}
-
-type ResolutionIndication interface{}
-
-type ElementResolution interface{}
-
 type ArrayElementResolution struct {
 	ResolutionIndication
 }
@@ -492,58 +317,8 @@ type RecordElementResolution struct {
 	ReslutionIndication     ResolutionIndication
 }
 
-type TypeMark interface{}
-
-type Constraint interface{}
-
 type ElementConstraint interface{}
 
-type ObjectDeclaration interface{}
-
-type VariableDeclaration struct {
-	IdentifierList        IdentifierList
-	SubtypeIndication     SubtypeIndication
-	GenericMapAspect      *GenericMapAspect
-	ConditionalExpression *ConditionalExpression
-}
-
-type FileDeclaration struct {
-	IdentifierList      IdentifierList
-	SubtypeIndication   SubtypeIndication
-	FileOpenInformation *FileOpenInformation
-}
-
-type FileOpenInformation struct {
-	FileOpenKindExpression *FileOpenKindExpression
-	FileLogicalName        FileLogicalName
-}
-
-type FileLogicalName struct {
-	StringLiteral
-}
-
-type InterfaceDeclaration interface{}
-
-type InterfaceObjectDeclaration interface{}
-
-type InterfaceConstantDeclaration struct {
-	IdentifierList              IdentifierList
-	InterfaceTypeIndication     InterfaceTypeIndication
-	StaticConditionalExpression *StaticConditionalExpression
-}
-
-type InterfaceSignalDeclaration struct {
-	IdentifierList              IdentifierList
-	Mode                        *Mode
-	InterfaceTypeIndication     InterfaceTypeIndication
-	StaticConditionalExpression *StaticConditionalExpression
-}
-
-type InterfaceFileDeclaration struct {
-	IdentifierList    IdentifierList
-	SubtypeIndication SubtypeIndication
-}
-
 type InterfaceTypeIndication interface{}
 
 type ModeIndication interface{}
@@ -554,10 +329,6 @@ type SimpleModeIndication struct {
 	StaticConditionalExpression *StaticConditionalExpression
 }
 
-type Mode struct {
-	Token token.Token
-}
-
 type ModeViewIndication interface{}
 
 type RecordModeViewIndication struct {
@@ -599,142 +370,509 @@ type ElementArrayModeViewIndication struct {
     ModeViewName ModeViewName
 }
 
-type InterfaceTypeDeclaration struct {
-    Type Type
+type GroupTemplateDeclaration struct{
     Identifier Identifier
-    IncompleteTypeDefintion *IncompleteTypeDefintion
+    EntityClassEntryList EntityClassEntryList
 }
 
-
-type InterfaceSubprogramDeclaration struct {
-    InterfaceSubprogramSpecification InterfaceSubprogramSpecification
-    InterfaceSubprogramDefault *InterfaceSubprogramDefault
+type EntityClassEntryList struct{
+    EntityClassEntry EntityClassEntry
+    EntityClassEntries *[] EntityClassEntry
 }
 
-type InterfaceSubprogramSpecification interface{}
-
-type IntefaceProcedureSpecification struct {
-    Designator Designator
-    FormalParameterList *FormalParameterList
+type EntityClassEntry struct{
+    EntityClass EntityClass
 }
 
-type InterfaceFunctionSpecification struct {
-    Designator Designator
-    FormalParameterList *FormalParameterList
-    TypeMark TypeMark
+type GroupDeclaration struct{
+    Identifier  Identifier
+    GroupTemplateName GroupTemplateName
+    GroupConstituentList GroupConstituentList
 }
-
-type InterfaceSubprogramDefault interface{}
-
-type InterfacePackageDeclaration struct {
-    Identifier Identifier
-    UnstantiatedPackageName UninstantiatedPackageName
-    InterfaceGenericMapAspect InterfaceGenericMapAspect
+type GroupConstituentList struct{
+    GroupConstituent GroupConstituent
+    GroupConstituents *[]GroupConstituent
 }
 
-type InterfaceGenericMapAspect interface{}
-
-type InterfaceList struct {
-    InterfaceElement InterfaceElement
-    InterfaceElementList *[]InterfaceElement
-}
+type GroupConstituent interface{}
+*/
 
-type InterfaceElement struct {
-    InterfaceDeclaration
-}
+// 4 Subprograms and packages
+type SubprogramSpecification interface{}
 
-type GenericClause struct {
-    GenericList GenericList
+// ProcedureSpecification is procedure designator [ ( formal_parameter_list ) ]
+type ProcedureSpecification struct {
+	Designator          Designator
+	FormalParameterList *InterfaceList
+	Node
 }
 
-type GenericList struct {
-    GenericInterfaceList
+// FunctionSpecification is [ pure | impure ] function designator [ ( formal_parameter_list ) ] return type_mark
+type FunctionSpecification struct {
+	Purity              token.Token // PURE, IMPURE or ILLEGAL if omitted
+	Designator          Designator
+	FormalParameterList *InterfaceList
+	ReturnType          Name
+	Node
 }
 
+// Designator is an Identifier or an OperatorSymbol
+type Designator interface{}
 
-type PortClause struct {
-    PortList PortList
+// SubprogramDeclaration is subprogram_specification ;
+type SubprogramDeclaration struct {
+	SubprogramSpecification SubprogramSpecification
+	Node
 }
 
-type PortList struct {
-    PortInterfaceList
+// SubprogramBody is subprogram_specification is declarative_part begin statement_part
+// end [ procedure | function ] [ designator ] ;
+type SubprogramBody struct {
+	SubprogramSpecification    SubprogramSpecification
+	SubprogramDeclarativeItems []BlockDeclarativeItem // same items as in a block
+	SequentialStatements       []SequentialStatement
+	Designator                 Designator // closing designator, or nil
+	Node
 }
 
-type AssociationList struct {
-    AssociationElement AssociationElement
-    AssociationElementList *[]AssociationElement
+// SubprogramInstantiationDeclaration is the VHDL-2008 subprogram_kind designator is new
+// uninstantiated_subprogram_name [ signature ] [ generic_map_aspect ] ;
+type SubprogramInstantiationDeclaration struct {
+	Kind                         token.Token // PROCEDURE or FUNCTION
+	Designator                   Designator
+	UninstantiatedSubprogramName Name
+	Signature                    *Signature
+	GenericMapAspect             *GenericMapAspect
+	Node
 }
 
-type AssociationElement struct {
-    FormalPart *FormalPart
-    ActualPart ActualPart
+// PackageInstantiationDeclaration is the VHDL-2008
+// package identifier is new uninstantiated_package_name [ generic_map_aspect ] ;
+type PackageInstantiationDeclaration struct {
+	Identifier                Identifier
+	UninstantiatedPackageName Name
+	GenericMapAspect          *GenericMapAspect
+	Node
 }
 
-type FormalPart interface{}
+// Signature is [ [ type_mark { , type_mark } ] [ return type_mark ] ]
+type Signature struct {
+	TypeMarks      []Name
+	ReturnTypeMark Name // or nil
+	Node
+}
 
-type FormalDesignator interface{}
+// 5 Types
+type Constraint interface{}
 
-type ActualPart interface{}
+// RangeConstraint is range range
+type RangeConstraint struct {
+	Range Range
+	Node
+}
 
-type ActualDesignator interface{}
+// Range is a SimpleRange or a range attribute name such as x'range
+type Range interface{}
 
-type GenericMapAspect struct {
-    GenericAssociationList GenericAssociationList
+// SimpleRange is left to right or left downto right
+type SimpleRange struct {
+	Left      Expression
+	Direction token.Token // TO or DOWNTO
+	Right     Expression
+	Node
 }
 
-type PortMapAspect struct {
-    PortAssociationList PortAssociationList
+// IndexConstraint is ( discrete_range { , discrete_range } ) followed by the
+// constraint of the elements of an array of arrays, if any
+type IndexConstraint struct {
+	DiscreteRanges    []DiscreteRange
+	ElementConstraint Constraint
+	Node
+}
+
+// DiscreteRange is a Range, a SubtypeIndication or the OPEN keyword
+type DiscreteRange interface{}
+
+type TypeDefinition interface{}
+
+// EnumerationTypeDefinition is ( enumeration_literal { , enumeration_literal } )
+type EnumerationTypeDefinition struct {
+	EnumerationLiterals []EnumerationLiteral
+	Node
+}
+
+// EnumerationLiteral is an Identifier or a CharacterLiteral
+type EnumerationLiteral interface{}
+
+// RangeTypeDefinition is the range constraint of an integer or a floating point type
+// definition, they only differ by the type of the bounds
+type RangeTypeDefinition struct {
+	RangeConstraint RangeConstraint
+	Node
+}
+
+// PhysicalTypeDefinition is range_constraint units primary_unit_declaration
+// { secondary_unit_declaration } end units [ simple_name ]
+type PhysicalTypeDefinition struct {
+	RangeConstraint           RangeConstraint
+	PrimaryUnitDeclaration    Identifier
+	SecondaryUnitDeclarations []SecondaryUnitDeclaration
+	PhysicalTypeSimpleName    *SimpleName
+	Node
+}
+
+// SecondaryUnitDeclaration is identifier = physical_literal ;
+type SecondaryUnitDeclaration struct {
+	Identifier      Identifier
+	PhysicalLiteral Expression
+	Node
+}
+
+// UnboundedArrayDefinition is array ( type_mark range <> { , type_mark range <> } ) of subtype_indication
+type UnboundedArrayDefinition struct {
+	IndexSubtypeDefinitions  []Name
+	ElementSubtypeIndication SubtypeIndication
+	Node
+}
+
+// ConstrainedArrayDefinition is array index_constraint of subtype_indication
+type ConstrainedArrayDefinition struct {
+	IndexConstraint          IndexConstraint
+	ElementSubtypeIndication SubtypeIndication
+	Node
+}
+
+// RecordTypeDefinition is record element_declaration { element_declaration } end record [ simple_name ]
+type RecordTypeDefinition struct {
+	ElementDeclarations  []ElementDeclaration
+	RecordTypeSimpleName *SimpleName
+	Node
+}
+
+// ElementDeclaration is identifier_list : subtype_indication ;
+type ElementDeclaration struct {
+	IdentifierList    IdentifierList
+	SubtypeIndication SubtypeIndication
+	Node
+}
+
+// AccessTypeDefinition is access subtype_indication
+type AccessTypeDefinition struct {
+	SubtypeIndication SubtypeIndication
+	Node
+}
+
+// FileTypeDefinition is file of type_mark
+type FileTypeDefinition struct {
+	TypeMark Name
+	Node
 }
 
+// ProtectedTypeDeclaration is protected declarative_part end protected [ simple_name ]
+type ProtectedTypeDeclaration struct {
+	ProtectedTypeDeclarativeItems []BlockDeclarativeItem
+	ProtectedTypeSimpleName       *SimpleName
+	Node
+}
+
+// ProtectedTypeBody is protected body declarative_part end protected body [ simple_name ]
+type ProtectedTypeBody struct {
+	ProtectedTypeBodyDeclarativeItems []BlockDeclarativeItem
+	ProtectedTypeSimpleName           *SimpleName
+	Node
+}
+
+// 6 Declarations
+type IdentifierList []Identifier
+
+// SubtypeIndication is [ resolution_indication ] type_mark [ constraint ]
+type SubtypeIndication struct {
+	ResolutionIndication ResolutionIndication // or nil
+	TypeMark             Name
+	Constraint           Constraint // or nil
+	Node
+}
+
+// ResolutionIndication is a resolution function name or an ElementResolution
+type ResolutionIndication interface{}
+
+// ElementResolution is ( resolution_indication ) for the elements of an array
+type ElementResolution struct {
+	ResolutionIndication ResolutionIndication
+	Node
+}
+
+// TypeDeclaration is a FullTypeDeclaration or an IncompleteTypeDeclaration
+type TypeDeclaration interface{}
+
+// FullTypeDeclaration is type identifier is type_definition ;
+type FullTypeDeclaration struct {
+	Identifier     Identifier
+	TypeDefinition TypeDefinition
+	Node
+}
+
+// IncompleteTypeDeclaration is type identifier ;
+type IncompleteTypeDeclaration struct {
+	Identifier Identifier
+	Node
+}
+
+// SubtypeDeclaration is subtype identifier is subtype_indication ;
+type SubtypeDeclaration struct {
+	Identifier        Identifier
+	SubtypeIndication SubtypeIndication
+	Node
+}
+
+// ConstantDeclaration is constant identifier_list : subtype_indication [ := expression ] ;
+type ConstantDeclaration struct {
+	IdentifierList    IdentifierList
+	SubtypeIndication SubtypeIndication
+	Expression        Expression // nil for a deferred constant
+	Node
+}
+
+// SignalDeclaration is signal identifier_list : subtype_indication [ register | bus ] [ := expression ] ;
+type SignalDeclaration struct {
+	IdentifierList    IdentifierList
+	SubtypeIndication SubtypeIndication
+	SignalKind        token.Token // REGISTER, BUS or ILLEGAL if omitted
+	Expression        Expression  // or nil
+	Node
+}
+
+// VariableDeclaration is [ shared ] variable identifier_list : subtype_indication [ := expression ] ;
+type VariableDeclaration struct {
+	Shared            bool
+	IdentifierList    IdentifierList
+	SubtypeIndication SubtypeIndication
+	Expression        Expression // or nil
+	Node
+}
+
+// FileDeclaration is file identifier_list : subtype_indication [ file_open_information ] ;
+type FileDeclaration struct {
+	IdentifierList      IdentifierList
+	SubtypeIndication   SubtypeIndication
+	FileOpenInformation *FileOpenInformation
+	Node
+}
+
+// FileOpenInformation is [ open file_open_kind_expression ] is file_logical_name
+type FileOpenInformation struct {
+	FileOpenKindExpression Expression // or nil
+	FileLogicalName        Expression
+	Node
+}
+
+// AliasDeclaration is alias alias_designator [ : subtype_indication ] is name [ signature ] ;
 type AliasDeclaration struct {
-    AliasDesignator AliasDesignator
-    SubtypeIndication *SubtypeIndication
-    Name Name
-    Signature *Signature
+	AliasDesignator   AliasDesignator
+	SubtypeIndication *SubtypeIndication
+	Name              Name
+	Signature         *Signature
+	Node
 }
 
+// AliasDesignator is an Identifier, a CharacterLiteral or an OperatorSymbol
 type AliasDesignator interface{}
 
-
+// AttributeDeclaration is attribute identifier : type_mark ;
 type AttributeDeclaration struct {
-    Identifier Identifier
-    TypeMark TypeMark
+	Identifier Identifier
+	TypeMark   Name
+	Node
 }
 
-type ComponentDeclaration struct{
-    Identifier Identifier
-    LocalGenericClause *LocalGenericClause
-    LocalPortClause *LocalPortClause
-    ComponentSimpleName *ComponentSimpleName
+// AttributeSpecification is attribute attribute_designator of entity_name_list : entity_class is expression ;
+type AttributeSpecification struct {
+	AttributeDesignator Identifier
+	EntityNameList      []EntityDesignator // empty for others and all
+	EntityNameKeyword   token.Token        // OTHERS, ALL or ILLEGAL for a list of names
+	EntityClass         token.Token
+	Expression          Expression
+	Node
 }
 
-type GroupTemplateDeclaration struct{
-    Identifier Identifier
-    EntityClassEntryList EntityClassEntryList
+// EntityDesignator is entity_tag [ signature ], the tag is a simple name, a character
+// literal or an operator symbol
+type EntityDesignator struct {
+	EntityTag Name
+	Signature *Signature
+	Node
 }
 
-type EntityClassEntryList struct{
-    EntityClassEntry EntityClassEntry
-    EntityClassEntries *[] EntityClassEntry
+// ComponentDeclaration is component identifier [ is ] [ generic_clause ] [ port_clause ]
+// end component [ simple_name ] ;
+type ComponentDeclaration struct {
+	Identifier          Identifier
+	LocalGenericClause  *FormalGenericClause
+	LocalPortClause     *FormalPortClause
+	ComponentSimpleName *SimpleName
+	Node
 }
 
-type EntityClassEntry struct{
-    EntityClass EntityClass
+// ConfigurationSpecification is for component_specification binding_indication ;
+type ConfigurationSpecification struct {
+	InstantiationList    []Identifier // instance labels, empty for others and all
+	InstantiationKeyword token.Token  // OTHERS, ALL or ILLEGAL for a list of labels
+	ComponentName        Name
+	BindingIndication    BindingIndication
+	Node
 }
 
-type GroupDeclaration struct{
-    Identifier  Identifier
-    GroupTemplateName GroupTemplateName
-    GroupConstituentList GroupConstituentList
+// BindingIndication is [ use entity_aspect ] [ generic_map_aspect ] [ port_map_aspect ]
+type BindingIndication struct {
+	EntityAspect     EntityAspect // or nil
+	GenericMapAspect *GenericMapAspect
+	PortMapAspect    *PortMapAspect
+	Node
 }
-type GroupConstituentList struct{
-    GroupConstituent GroupConstituent
-    GroupConstituents *[]GroupConstituent
+
+// EntityAspect is an EntityAspectEntity, an EntityAspectConfiguration or the OPEN keyword
+type EntityAspect interface{}
+
+// EntityAspectEntity is entity entity_name [ ( architecture_identifier ) ]
+type EntityAspectEntity struct {
+	EntityName             Name
+	ArchitectureIdentifier *Identifier
+	Node
 }
 
-type GroupConstituent interface{}
-*/
+// EntityAspectConfiguration is configuration configuration_name
+type EntityAspectConfiguration struct {
+	ConfigurationName Name
+	Node
+}
+
+// DisconnectionSpecification is disconnect guarded_signal_specification after time_expression ;
+type DisconnectionSpecification struct {
+	SignalList    []Name      // guarded signals, empty for others and all
+	SignalKeyword token.Token // OTHERS, ALL or ILLEGAL for a list of names
+	TypeMark      Name
+	After         Expression
+	Node
+}
+
+// GroupTemplateDeclaration is group identifier is ( entity_class_entry_list ) ;
+type GroupTemplateDeclaration struct {
+	Identifier         Identifier
+	EntityClassEntries []EntityClassEntry
+	Node
+}
+
+// EntityClassEntry is entity_class [ <> ]
+type EntityClassEntry struct {
+	EntityClass token.Token
+	Box         bool
+	Node
+}
+
+// GroupDeclaration is group identifier : group_template_name ( group_constituent_list ) ;
+type GroupDeclaration struct {
+	Identifier        Identifier
+	GroupTemplateName Name
+	GroupConstituents []Name // names and character literals
+	Node
+}
+
+// InterfaceList holds the declarations of a generic, port or parameter list
+type InterfaceList struct {
+	InterfaceElements []InterfaceDeclaration
+	Node
+}
+
+type InterfaceDeclaration interface{}
+
+// InterfaceConstantDeclaration is [ constant ] identifier_list : [ in ] subtype_indication [ := static_expression ]
+type InterfaceConstantDeclaration struct {
+	IdentifierList    IdentifierList
+	Mode              *Mode
+	SubtypeIndication SubtypeIndication
+	DefaultExpression Expression // or nil
+	Node
+}
+
+// InterfaceSignalDeclaration is [ signal ] identifier_list : [ mode ] subtype_indication [ bus ] [ := static_expression ]
+type InterfaceSignalDeclaration struct {
+	IdentifierList    IdentifierList
+	Mode              *Mode
+	SubtypeIndication SubtypeIndication
+	Bus               bool
+	DefaultExpression Expression // or nil
+	Node
+}
+
+// InterfaceVariableDeclaration is [ variable ] identifier_list : [ mode ] subtype_indication [ := static_expression ]
+type InterfaceVariableDeclaration struct {
+	IdentifierList    IdentifierList
+	Mode              *Mode
+	SubtypeIndication SubtypeIndication
+	DefaultExpression Expression // or nil
+	Node
+}
+
+// InterfaceFileDeclaration is file identifier_list : subtype_indication
+type InterfaceFileDeclaration struct {
+	IdentifierList    IdentifierList
+	SubtypeIndication SubtypeIndication
+	Node
+}
+
+// InterfaceTypeDeclaration is the VHDL-2008 generic type type identifier
+type InterfaceTypeDeclaration struct {
+	Identifier Identifier
+	Node
+}
+
+// InterfaceSubprogramDeclaration is a VHDL-2008 generic subprogram with its default,
+// is subprogram_name or is <>
+type InterfaceSubprogramDeclaration struct {
+	SubprogramSpecification SubprogramSpecification
+	Default                 Name // or nil
+	Box                     bool // set for is <>
+	Node
+}
+
+// InterfacePackageDeclaration is the VHDL-2008 generic package
+// package identifier is new uninstantiated_package_name interface_generic_map_aspect
+type InterfacePackageDeclaration struct {
+	Identifier                Identifier
+	UninstantiatedPackageName Name
+	GenericMapAspect          GenericMapAspect
+	Node
+}
+
+// Mode is the IN, OUT, INOUT, BUFFER or LINKAGE keyword
+type Mode struct {
+	Token token.Token
+	Node
+}
 
+// AssociationElement is [ formal_part => ] actual_part
+type AssociationElement struct {
+	Formal Name       // or nil for a positional association
+	Actual Expression // an expression, a SimpleRange or the OPEN keyword
+	Node
+}
+
+// GenericMapAspect is generic map ( generic_association_list ), an interface package
+// can also have generic map ( <> ) and generic map ( default )
+type GenericMapAspect struct {
+	AssociationList []AssociationElement
+	Box             bool
+	Default         bool
+	Node
+}
+
+// PortMapAspect is port map ( port_association_list )
+type PortMapAspect struct {
+	AssociationList []AssociationElement
+	Node
+}
+
+// 8 Names
 type UseClause struct {
 	SelectedName     SelectedName
 	SelectedNameList []SelectedName
@@ -770,24 +908,583 @@ type OperatorSymbol struct {
     Symbol string
 }
 
+// IndexedName is prefix ( expression { , expression } ), a function call with
+// positional parameters has the same form
+type IndexedName struct {
+	Prefix      Prefix
+	Expressions []Expression
+	Node
+}
+
+// SliceName is prefix ( discrete_range )
+type SliceName struct {
+	Prefix        Prefix
+	DiscreteRange DiscreteRange
+	Node
+}
+
+// AttributeName is prefix ' attribute_designator, the designator can be a reserved
+// word such as range or subtype
+type AttributeName struct {
+	Prefix     Prefix
+	Designator Identifier
+	Node
+}
+
+// FunctionCall is function_name ( actual_parameter_part ) with named associations
+type FunctionCall struct {
+	Name                  Name
+	ParameterAssociations []AssociationElement
+	Node
+}
+
 type SuffixKeyword struct {
 	Token token.Token
 	Suffix
 }
 
 type Identifier struct {
-	Identifier string
+	Identifier string // identifier as spelled in the source
+	Extended   bool   // set for extended identifiers such as \bus[3]\, which are case sensitive
 	Node
 }
 
+// Normalized returns the identifier in the form used to compare it against other
+// identifiers, the original spelling is kept in Identifier.
+func (id Identifier) Normalized() string {
+	return token.NormalizeIdent(id.Identifier)
+}
+
 type Keyword struct {   
     Token token.Token
     Value string
 }
 
+// 9 Expressions
+type Expression interface{}
+
+// BasicLit is an abstract literal, a character literal, a string literal, a bit string
+// literal or null
+type BasicLit struct {
+	Kind  token.Token
+	Value string
+	Node
+}
+
+// PhysicalLiteral is [ abstract_literal ] unit_name
+type PhysicalLiteral struct {
+	AbstractLiteral BasicLit
+	UnitName        Name
+	Node
+}
+
+// UnaryExpr is a sign, abs, not, a unary logical operator or ?? applied to an operand
+type UnaryExpr struct {
+	Op token.Token
+	X  Expression
+	Node
+}
+
+// BinaryExpr is an expression with a binary operator
+type BinaryExpr struct {
+	X     Expression
+	Op    token.Token
+	OpPos token.Pos
+	Y     Expression
+	Node
+}
+
+// ParenExpr is ( expression )
+type ParenExpr struct {
+	X Expression
+	Node
+}
+
+// Aggregate is ( element_association { , element_association } )
+type Aggregate struct {
+	ElementAssociations []ElementAssociation
+	Node
+}
+
+// ElementAssociation is [ choices => ] expression
+type ElementAssociation struct {
+	Choices    []Choice
+	Expression Expression
+	Node
+}
+
+// QualifiedExpression is type_mark ' ( expression ) or type_mark ' aggregate
+type QualifiedExpression struct {
+	TypeMark Name
+	Operand  Expression
+	Node
+}
+
+// Allocator is new subtype_indication or new qualified_expression
+type Allocator struct {
+	Operand Expression
+	Node
+}
+
+// 10 Sequential statements
+type SequentialStatement interface{}
+
+// Assertion is assert condition [ report expression ] [ severity expression ]
+type Assertion struct {
+	Condition Expression
+	Report    Expression // or nil
+	Severity  Expression // or nil
+	Node
+}
+
+// ProcedureCall is procedure_name [ ( actual_parameter_part ) ], the parameters are
+// part of the name as in a function call
+type ProcedureCall struct {
+	Name Name
+	Node
+}
+
+// DelayMechanism is transport or [ reject time_expression ] inertial
+type DelayMechanism struct {
+	Transport  bool
+	RejectTime Expression // or nil
+	Node
+}
+
+// Waveform is waveform_element { , waveform_element } or unaffected
+type Waveform struct {
+	WaveformElements []WaveformElement
+	Unaffected       bool
+	Node
+}
+
+// WaveformElement is value_expression [ after time_expression ], the value of a null
+// transaction is the null literal
+type WaveformElement struct {
+	Value Expression
+	After Expression // or nil
+	Node
+}
+
+// Choice is a simple expression, a DiscreteRange or the OTHERS keyword
+type Choice interface{}
+
+// ParameterSpecification is identifier in discrete_range
+type ParameterSpecification struct {
+	Identifier    Identifier
+	DiscreteRange DiscreteRange
+	Node
+}
+
+// WaitStatement is [ label : ] wait [ on sensitivity_list ] [ until condition ] [ for time_expression ] ;
+type WaitStatement struct {
+	Label             *Identifier
+	SensitivityClause []Name
+	ConditionClause   Expression // or nil
+	TimeoutClause     Expression // or nil
+	Node
+}
+
+// AssertionStatement is [ label : ] assertion ;
+type AssertionStatement struct {
+	Label     *Identifier
+	Assertion Assertion
+	Node
+}
+
+// ReportStatement is [ label : ] report expression [ severity expression ] ;
+type ReportStatement struct {
+	Label    *Identifier
+	Report   Expression
+	Severity Expression // or nil
+	Node
+}
+
+// SimpleSignalAssignment is [ label : ] target <= [ delay_mechanism ] waveform ;
+type SimpleSignalAssignment struct {
+	Label          *Identifier
+	Target         Name // or an Aggregate
+	DelayMechanism *DelayMechanism
+	Waveform       Waveform
+	Node
+}
+
+// SimpleForceAssignment is [ label : ] target <= force [ force_mode ] expression ;
+type SimpleForceAssignment struct {
+	Label      *Identifier
+	Target     Name
+	ForceMode  token.Token // IN, OUT or ILLEGAL if there is no mode
+	Expression Expression
+	Node
+}
+
+// SimpleReleaseAssignment is [ label : ] target <= release [ force_mode ] ;
+type SimpleReleaseAssignment struct {
+	Label     *Identifier
+	Target    Name
+	ForceMode token.Token // IN, OUT or ILLEGAL if there is no mode
+	Node
+}
+
+// ConditionalSignalAssignment is [ label : ] target <= [ delay_mechanism ] waveform when
+// condition { else waveform when condition } [ else waveform ] ;
+type ConditionalSignalAssignment struct {
+	Label                *Identifier
+	Target               Name // or an Aggregate
+	DelayMechanism       *DelayMechanism
+	ConditionalWaveforms []ConditionalWaveform
+	Node
+}
+
+// SelectedSignalAssignment is [ label : ] with expression select [ ? ] target <=
+// [ delay_mechanism ] selected_waveforms ;
+type SelectedSignalAssignment struct {
+	Label             *Identifier
+	Expression        Expression
+	Matching          bool // select ?
+	Target            Name // or an Aggregate
+	DelayMechanism    *DelayMechanism
+	SelectedWaveforms []SelectedWaveform
+	Node
+}
+
+// ConditionalForceAssignment is [ label : ] target <= force [ force_mode ] expression when
+// condition { else expression when condition } [ else expression ] ;
+type ConditionalForceAssignment struct {
+	Label                  *Identifier
+	Target                 Name
+	ForceMode              token.Token // IN, OUT or ILLEGAL if there is no mode
+	ConditionalExpressions []ConditionalExpression
+	Node
+}
+
+// SelectedForceAssignment is [ label : ] with expression select [ ? ] target <= force
+// [ force_mode ] expression when choices { , expression when choices } ;
+type SelectedForceAssignment struct {
+	Label               *Identifier
+	Expression          Expression
+	Matching            bool // select ?
+	Target              Name
+	ForceMode           token.Token // IN, OUT or ILLEGAL if there is no mode
+	SelectedExpressions []SelectedExpression
+	Node
+}
+
+// VariableAssignment is [ label : ] target := expression ;
+type VariableAssignment struct {
+	Label      *Identifier
+	Target     Name // or an Aggregate
+	Expression Expression
+	Node
+}
+
+// ConditionalVariableAssignment is [ label : ] target := expression when condition
+// { else expression when condition } [ else expression ] ;
+type ConditionalVariableAssignment struct {
+	Label                  *Identifier
+	Target                 Name // or an Aggregate
+	ConditionalExpressions []ConditionalExpression
+	Node
+}
+
+// ConditionalExpression is expression [ when condition ], only the last one has no condition
+type ConditionalExpression struct {
+	Expression Expression
+	Condition  Expression // or nil
+	Node
+}
+
+// SelectedVariableAssignment is [ label : ] with expression select [ ? ] target :=
+// expression when choices { , expression when choices } ;
+type SelectedVariableAssignment struct {
+	Label               *Identifier
+	Expression          Expression
+	Matching            bool // select ?
+	Target              Name // or an Aggregate
+	SelectedExpressions []SelectedExpression
+	Node
+}
+
+// SelectedExpression is expression when choices
+type SelectedExpression struct {
+	Expression Expression
+	Choices    []Choice
+	Node
+}
+
+// ProcedureCallStatement is [ label : ] procedure_call ;
+type ProcedureCallStatement struct {
+	Label         *Identifier
+	ProcedureCall ProcedureCall
+	Node
+}
+
+// IfStatement is [ label : ] if condition then { sequential_statement }
+// { elsif condition then ... } [ else ... ] end if [ label ] ;
+type IfStatement struct {
+	Label        *Identifier
+	Alternatives []IfAlternative
+	IfLabel      *SimpleName
+	Node
+}
+
+// IfAlternative is a branch of an if statement, the else branch has no condition
+type IfAlternative struct {
+	Condition            Expression // or nil
+	SequentialStatements []SequentialStatement
+	Node
+}
+
+// CaseStatement is [ label : ] case [ ? ] expression is case_statement_alternative
+// { case_statement_alternative } end case [ ? ] [ label ] ;
+type CaseStatement struct {
+	Label        *Identifier
+	Matching     bool // case ?
+	Expression   Expression
+	Alternatives []CaseStatementAlternative
+	CaseLabel    *SimpleName
+	Node
+}
+
+// CaseStatementAlternative is when choices => { sequential_statement }
+type CaseStatementAlternative struct {
+	Choices              []Choice
+	SequentialStatements []SequentialStatement
+	Node
+}
+
+// LoopStatement is [ label : ] [ iteration_scheme ] loop { sequential_statement }
+// end loop [ label ] ;
+type LoopStatement struct {
+	Label                *Identifier
+	IterationScheme      IterationScheme // or nil
+	SequentialStatements []SequentialStatement
+	LoopLabel            *SimpleName
+	Node
+}
+
+// IterationScheme is a WhileScheme or a ParameterSpecification for a for loop
+type IterationScheme interface{}
+
+// WhileScheme is while condition
+type WhileScheme struct {
+	Condition Expression
+	Node
+}
+
+// NextStatement is [ label : ] next [ loop_label ] [ when condition ] ;
+type NextStatement struct {
+	Label     *Identifier
+	LoopLabel *SimpleName
+	Condition Expression // or nil
+	Node
+}
+
+// ExitStatement is [ label : ] exit [ loop_label ] [ when condition ] ;
+type ExitStatement struct {
+	Label     *Identifier
+	LoopLabel *SimpleName
+	Condition Expression // or nil
+	Node
+}
+
+// ReturnStatement is [ label : ] return [ expression ] ;
+type ReturnStatement struct {
+	Label      *Identifier
+	Expression Expression // or nil
+	Node
+}
+
+// NullStatement is [ label : ] null ;
+type NullStatement struct {
+	Label *Identifier
+	Node
+}
+
+// 11 Concurrent statements
+
+// BlockStatement is label : block [ ( guard_condition ) ] [ is ] block_header
+// declarative_part begin { concurrent_statement } end block [ label ] ;
+type BlockStatement struct {
+	Label                 *Identifier
+	GuardCondition        Expression // or nil
+	BlockHeader           BlockHeader
+	BlockDeclarativeItems []BlockDeclarativeItem
+	ConcurrentStatements  []ConcurrentStatement
+	BlockLabel            *SimpleName
+	Node
+}
+
+// BlockHeader is [ generic_clause [ generic_map_aspect ; ] ] [ port_clause [ port_map_aspect ; ] ]
+type BlockHeader struct {
+	GenericClause    *FormalGenericClause
+	GenericMapAspect *GenericMapAspect
+	PortClause       *FormalPortClause
+	PortMapAspect    *PortMapAspect
+	Node
+}
+
+// ProcessStatement is [ label : ] [ postponed ] process [ ( sensitivity_list ) ] [ is ]
+// declarative_part begin { sequential_statement } end [ postponed ] process [ label ] ;
+type ProcessStatement struct {
+	Label                   *Identifier
+	Postponed               bool
+	SensitivityList         []Name
+	All                     bool // process ( all )
+	ProcessDeclarativeItems []BlockDeclarativeItem
+	SequentialStatements    []SequentialStatement
+	ProcessLabel            *SimpleName
+	Node
+}
+
+// ConcurrentProcedureCallStatement is [ label : ] [ postponed ] procedure_call ;
+type ConcurrentProcedureCallStatement struct {
+	Label         *Identifier
+	Postponed     bool
+	ProcedureCall ProcedureCall
+	Node
+}
+
+// ConcurrentAssertionStatement is [ label : ] [ postponed ] assertion ;
+type ConcurrentAssertionStatement struct {
+	Label     *Identifier
+	Postponed bool
+	Assertion Assertion
+	Node
+}
+
+// ConcurrentSimpleSignalAssignment is [ label : ] [ postponed ] target <= [ guarded ]
+// [ delay_mechanism ] waveform ;
+type ConcurrentSimpleSignalAssignment struct {
+	Label          *Identifier
+	Postponed      bool
+	Target         Name // or an Aggregate
+	Guarded        bool
+	DelayMechanism *DelayMechanism
+	Waveform       Waveform
+	Node
+}
+
+// ConcurrentConditionalSignalAssignment is [ label : ] [ postponed ] target <= [ guarded ]
+// [ delay_mechanism ] waveform when condition { else waveform when condition } [ else waveform ] ;
+type ConcurrentConditionalSignalAssignment struct {
+	Label                *Identifier
+	Postponed            bool
+	Target               Name // or an Aggregate
+	Guarded              bool
+	DelayMechanism       *DelayMechanism
+	ConditionalWaveforms []ConditionalWaveform
+	Node
+}
+
+// ConditionalWaveform is waveform [ when condition ], only the last one has no condition
+type ConditionalWaveform struct {
+	Waveform  Waveform
+	Condition Expression // or nil
+	Node
+}
+
+// ConcurrentSelectedSignalAssignment is [ label : ] [ postponed ] with expression select [ ? ]
+// target <= [ guarded ] [ delay_mechanism ] selected_waveforms ;
+type ConcurrentSelectedSignalAssignment struct {
+	Label             *Identifier
+	Postponed         bool
+	Expression        Expression
+	Matching          bool // select ?
+	Target            Name // or an Aggregate
+	Guarded           bool
+	DelayMechanism    *DelayMechanism
+	SelectedWaveforms []SelectedWaveform
+	Node
+}
+
+// SelectedWaveform is waveform when choices
+type SelectedWaveform struct {
+	Waveform Waveform
+	Choices  []Choice
+	Node
+}
+
+// ComponentInstantiationStatement is label : instantiated_unit [ generic_map_aspect ]
+// [ port_map_aspect ] ;
+type ComponentInstantiationStatement struct {
+	Label            *Identifier
+	InstantiatedUnit InstantiatedUnit
+	GenericMapAspect *GenericMapAspect
+	PortMapAspect    *PortMapAspect
+	Node
+}
+
+// InstantiatedUnit is an InstantiatedComponent, an EntityAspectEntity or an
+// EntityAspectConfiguration
+type InstantiatedUnit interface{}
+
+// InstantiatedComponent is [ component ] component_name
+type InstantiatedComponent struct {
+	ComponentName Name
+	Node
+}
+
+// ForGenerateStatement is label : for parameter_specification generate
+// generate_statement_body end generate [ label ] ;
+type ForGenerateStatement struct {
+	Label                  *Identifier
+	ParameterSpecification ParameterSpecification
+	GenerateStatementBody  GenerateStatementBody
+	GenerateLabel          *SimpleName
+	Node
+}
+
+// IfGenerateStatement is label : if [ alternative_label : ] condition generate
+// generate_statement_body { elsif ... } [ else ... ] end generate [ label ] ;
+type IfGenerateStatement struct {
+	Label         *Identifier
+	Alternatives  []IfGenerateAlternative
+	GenerateLabel *SimpleName
+	Node
+}
+
+// IfGenerateAlternative is a branch of an if generate, the else branch has no condition
+type IfGenerateAlternative struct {
+	AlternativeLabel      *Identifier
+	Condition             Expression // or nil
+	GenerateStatementBody GenerateStatementBody
+	Node
+}
+
+// CaseGenerateStatement is label : case expression generate
+// case_generate_alternative { case_generate_alternative } end generate [ label ] ;
+type CaseGenerateStatement struct {
+	Label         *Identifier
+	Expression    Expression
+	Alternatives  []CaseGenerateAlternative
+	GenerateLabel *SimpleName
+	Node
+}
+
+// CaseGenerateAlternative is when [ alternative_label : ] choices => generate_statement_body
+type CaseGenerateAlternative struct {
+	AlternativeLabel      *Identifier
+	Choices               []Choice
+	GenerateStatementBody GenerateStatementBody
+	Node
+}
+
+// GenerateStatementBody is [ declarative_part begin ] { concurrent_statement }
+// [ end [ alternative_label ] ; ]
+type GenerateStatementBody struct {
+	BlockDeclarativeItems []BlockDeclarativeItem
+	ConcurrentStatements  []ConcurrentStatement
+	AlternativeLabel      *SimpleName
+	Node
+}
+
 type File struct {
 	FileStart, FileEnd token.Pos // start and end of entire file
 	DesignUnits        []DesignUnit
+	Pragmas            []Pragma       // synthesis metacomments in source order
+	PragmaRegions      []PragmaRegion // regions hidden from synthesis by the pragmas
+	Protected          []ProtectedEnvelope
 }
 
 type DesignUnit struct {
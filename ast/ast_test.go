@@ -1,14 +1,23 @@
 package ast_test
 
 import (
-	"testing"
-    "vhdl/ast"
+	"fmt"
+	"vhdl/ast"
+	"vhdl/parser"
+	"vhdl/token"
 )
-//Test creating a ast for a architecture
 
-func ExampleInspect(){
-    src := `
-    entity test is
+//Test creating a ast for a entity
+
+func ExampleIdentifier() {
+	src := `
+    entity Test is
     end entity;
-    `;
+    `
+	var p parser.Parser
+	p.Init(token.NewFileSet(), "test.vhd", []byte(src), 0)
+	file, _ := p.ParseFile()
+	entity := file.DesignUnits[0].LibraryUnit.(ast.EntityDeclaration)
+	fmt.Println(entity.Identifier.Identifier, entity.Identifier.Normalized())
+	// Output: Test test
 }
@@ -1,3 +1,3 @@
 module vhdl
 
-go 1.21.6
+go 1.23
@@ -0,0 +1,166 @@
+package literal
+
+import (
+	"math/big"
+	"strings"
+)
+
+// maxExponent bounds the exponent of an abstract literal so an exact value can always be built.
+const maxExponent = 10000
+
+// abstract is an abstract literal (LRM 15.5) split in its parts
+//
+//	decimal_literal := integer [ . integer ] [ exponent ]
+//	based_literal := base # based_integer [ . based_integer ] # [ exponent ]
+type abstract struct {
+	base     int64
+	mantissa *big.Int // all the digits, integer and fraction part
+	scale    int      // number of digits of the fraction part
+	point    bool     // the literal has a fraction part
+	exponent int
+}
+
+// Int returns the value of an integer literal, an INT token or a BASED token without
+// a point. The exponent of an integer literal can not be negative.
+func Int(lit string) (*big.Int, error) {
+	a, err := parseAbstract(lit)
+	if err != nil {
+		return nil, err
+	}
+	if a.point {
+		return nil, errorf(0, "%s is not an integer literal", lit)
+	}
+	if a.exponent < 0 {
+		return nil, errorf(strings.LastIndexAny(lit, "eE"), "negative exponent in integer literal")
+	}
+	value := new(big.Int).Exp(big.NewInt(a.base), big.NewInt(int64(a.exponent)), nil)
+	return value.Mul(value, a.mantissa), nil
+}
+
+// Real returns the exact value of any abstract literal, an INT, REAL or BASED token.
+func Real(lit string) (*big.Rat, error) {
+	a, err := parseAbstract(lit)
+	if err != nil {
+		return nil, err
+	}
+	value := new(big.Rat).SetInt(a.mantissa)
+	exp := a.exponent - a.scale
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(a.base), big.NewInt(int64(abs(exp))), nil))
+	if exp < 0 {
+		return value.Quo(value, scale), nil
+	}
+	return value.Mul(value, scale), nil
+}
+
+func parseAbstract(lit string) (a abstract, err error) {
+	a.base = 10
+	i, digits, err := scanDigits(lit, 0, 10)
+	if err != nil {
+		return a, err
+	}
+	if digits == "" {
+		return a, errorf(0, "missing digits in abstract literal")
+	}
+	if i < len(lit) && (lit[i] == '#' || lit[i] == ':') {
+		//LRM 15.10 allows ':' to replace both '#' of a based literal
+		delimiter := lit[i]
+		base, _ := new(big.Int).SetString(digits, 10)
+		if !base.IsInt64() || base.Int64() < 2 || base.Int64() > 16 {
+			return a, errorf(0, "invalid base %s, the base must be between 2 and 16", lit[:i])
+		}
+		a.base = base.Int64()
+		if i, digits, err = scanDigits(lit, i+1, int(a.base)); err != nil {
+			return a, err
+		}
+		fraction := ""
+		if i < len(lit) && lit[i] == '.' {
+			a.point = true
+			if i, fraction, err = scanDigits(lit, i+1, int(a.base)); err != nil {
+				return a, err
+			}
+		}
+		if digits == "" || a.point && fraction == "" {
+			return a, errorf(i, "missing digits in based literal")
+		}
+		if i >= len(lit) || lit[i] != delimiter {
+			return a, errorf(i, "based literal not terminated, expected %q", delimiter)
+		}
+		i++
+		digits += fraction
+		a.scale = len(fraction)
+	} else if i < len(lit) && lit[i] == '.' {
+		a.point = true
+		fraction := ""
+		if i, fraction, err = scanDigits(lit, i+1, 10); err != nil {
+			return a, err
+		}
+		if fraction == "" {
+			return a, errorf(i, "missing digits in fractional part")
+		}
+		digits += fraction
+		a.scale = len(fraction)
+	}
+	a.mantissa, _ = new(big.Int).SetString(digits, int(a.base))
+
+	if i < len(lit) && (lit[i] == 'e' || lit[i] == 'E') {
+		start := i
+		i++
+		negative := false
+		if i < len(lit) && (lit[i] == '+' || lit[i] == '-') {
+			negative = lit[i] == '-'
+			i++
+		}
+		exp := ""
+		if i, exp, err = scanDigits(lit, i, 10); err != nil {
+			return a, err
+		}
+		if exp == "" {
+			return a, errorf(i, "exponent has no digits")
+		}
+		value, _ := new(big.Int).SetString(exp, 10)
+		if !value.IsInt64() || value.Int64() > maxExponent {
+			return a, errorf(start, "exponent %s is too large", lit[start:i])
+		}
+		a.exponent = int(value.Int64())
+		if negative {
+			a.exponent = -a.exponent
+		}
+	}
+	if i < len(lit) {
+		return a, errorf(i, "unexpected %q in abstract literal", lit[i])
+	}
+	return a, nil
+}
+
+// scanDigits reads the digits of base starting at lit[i], underlines are only allowed
+// between two digits. It returns the offset after the digits and the digits without underlines.
+func scanDigits(lit string, i int, base int) (int, string, error) {
+	var sb strings.Builder
+	start := i
+	//Letters are only digits of based literals with a base above 10
+	isDigit := func(c byte) bool { return isDecimal(c) || base > 10 && DigitValue(rune(c)) < 16 }
+	for ; i < len(lit); i++ {
+		c := lit[i]
+		if c == '_' {
+			if i == start || i+1 == len(lit) || lit[i-1] == '_' || !isDigit(lit[i+1]) {
+				return i, "", errorf(i, "underline must separate two digits")
+			}
+			continue
+		}
+		if !isDigit(c) {
+			break
+		}
+		if digit := DigitValue(rune(c)); digit >= base {
+			return i, "", errorf(i, "invalid digit %q in base %d literal", c, base)
+		}
+		sb.WriteByte(c)
+	}
+	return i, sb.String(), nil
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,98 @@
+package literal
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestInt(t *testing.T) {
+	tests := []struct {
+		lit  string
+		want string
+	}{
+		{"0", "0"},
+		{"4563", "4563"},
+		{"1_000_000", "1000000"},
+		{"1E6", "1000000"},
+		{"1e+3", "1000"},
+		{"2#1111_1111#", "255"},
+		{"16#FF#", "255"},
+		{"16#ff#", "255"},
+		{"016#0FF#", "255"},
+		{"8#377#", "255"},
+		{"2#1#E10", "1024"},
+		{"16:FF:", "255"},
+		{"123456789012345678901234567890", "123456789012345678901234567890"},
+	}
+	for _, test := range tests {
+		got, err := Int(test.lit)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.lit, err)
+			continue
+		}
+		if got.String() != test.want {
+			t.Errorf("%s: got %s, want %s", test.lit, got, test.want)
+		}
+	}
+}
+
+func TestReal(t *testing.T) {
+	tests := []struct {
+		lit  string
+		want string // numerator/denominator
+	}{
+		{"4.563", "4563/1000"},
+		{"6.023E+24", "6023000000000000000000000/1"},
+		{"1.0E-3", "1/1000"},
+		{"0.5", "1/2"},
+		{"3_1.4_1", "3141/100"},
+		{"16#F.F#E2", "4080/1"},
+		{"2#0.1#", "1/2"},
+		{"10", "10/1"},
+		{"2#1.1#E-1", "3/4"},
+	}
+	for _, test := range tests {
+		got, err := Real(test.lit)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.lit, err)
+			continue
+		}
+		want, _ := new(big.Rat).SetString(test.want)
+		if got.Cmp(want) != 0 {
+			t.Errorf("%s: got %s, want %s", test.lit, got, want)
+		}
+	}
+}
+
+func TestAbstractErrors(t *testing.T) {
+	tests := []struct {
+		lit    string
+		offset int
+	}{
+		{"1E-3", 1},
+		{"1.5", 0},
+		{"8#9#", 2},
+		{"2#102#", 4},
+		{"12#ABC#", 5},
+		{"1#0#", 0},
+		{"17#0#", 0},
+		{"16#FF", 5},
+		{"1__0", 1},
+		{"1_", 1},
+		{"1_E5", 1},
+		{"1E", 2},
+		{"16##", 3},
+		{"1E99999", 1},
+	}
+	for _, test := range tests {
+		_, err := Int(test.lit)
+		e, ok := err.(*Error)
+		if !ok {
+			t.Errorf("%s: got %v, want a literal error", test.lit, err)
+			continue
+		}
+		if e.Offset != test.offset {
+			t.Errorf("%s: error %q at offset %d, want %d", test.lit, e.Msg, e.Offset, test.offset)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+// Package literal evaluates the literals returned by the scanner.
+package literal
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Error describes a malformed literal, Offset is the byte offset of the problem inside the literal.
+type Error struct {
+	Offset int
+	Msg    string
+}
+
+func (e *Error) Error() string {
+	return e.Msg
+}
+
+func errorf(offset int, format string, args ...any) error {
+	return &Error{offset, fmt.Sprintf(format, args...)}
+}
+
+// BitString is a bit string literal split in its parts (LRM 15.8)
+//
+//	bit_string_literal := [ integer ] base_specifier " [ bit_value ] "
+type BitString struct {
+	Length int    // declared length, -1 if there is none
+	Base   string // base specifier in upper case: B, O, X, UB, UO, UX, SB, SO, SX or D
+	Value  string // bit value without the underlines
+
+	offsets []int // offset in the literal of each character of Value
+}
+
+// Signed reports whether the literal has a SB, SO or SX base specifier.
+func (b BitString) Signed() bool {
+	return b.Base[0] == 'S'
+}
+
+// ParseBitString splits a BIT_STR literal in its parts.
+func ParseBitString(lit string) (BitString, error) {
+	b := BitString{Length: -1}
+	i := 0
+	//Declared length
+	for i < len(lit) && (isDecimal(lit[i]) || lit[i] == '_') {
+		i++
+	}
+	if i > 0 {
+		length, ok := new(big.Int).SetString(strings.ReplaceAll(lit[:i], "_", ""), 10)
+		if !ok || !length.IsInt64() || length.Int64() > 1<<31 {
+			return b, errorf(0, "invalid bit string length %s", lit[:i])
+		}
+		b.Length = int(length.Int64())
+	}
+	//Base specifier
+	start := i
+	for i < len(lit) && lit[i] != '"' {
+		i++
+	}
+	b.Base = strings.ToUpper(lit[start:i])
+	switch b.Base {
+	case "B", "O", "X", "UB", "UO", "UX", "SB", "SO", "SX", "D":
+	default:
+		return b, errorf(start, "invalid base specifier %q", lit[start:i])
+	}
+	if i == len(lit) || len(lit)-i < 2 || lit[len(lit)-1] != '"' {
+		return b, errorf(i, "bit string literal not terminated")
+	}
+	//Bit value, underlines are only allowed between two characters
+	value := lit[i+1 : len(lit)-1]
+	offset := i + 1
+	var sb strings.Builder
+	for j := 0; j < len(value); j++ {
+		if value[j] == '_' {
+			if j == 0 || j == len(value)-1 || value[j-1] == '_' {
+				return b, errorf(offset+j, "underline must separate two characters of a bit value")
+			}
+			continue
+		}
+		sb.WriteByte(value[j])
+		b.offsets = append(b.offsets, offset+j)
+	}
+	b.Value = sb.String()
+	return b, nil
+}
+
+// Expand returns the string value of the literal, a string of the characters
+// '0' and '1' and any metavalue such as 'Z', 'X' or '-' written in the bit value.
+// The rules of LRM 15.8 apply:
+//   - each digit of an O or X bit value becomes 3 or 4 bits, any other character is
+//     replicated 3 or 4 times, digits must be valid for the base
+//   - a D bit value is a decimal number converted to the minimum number of bits
+//     needed to represent it (one bit for zero)
+//   - with a declared length the value is padded on the left with '0', or with its
+//     leftmost character for a signed literal, or truncated on the left. Only '0',
+//     or copies of the sign character for a signed literal, can be truncated.
+func (b BitString) Expand() (string, error) {
+	var bits string
+	switch b.Base[len(b.Base)-1] {
+	case 'B':
+		for i := 0; i < len(b.Value); i++ {
+			if c := b.Value[i]; isDecimal(c) && c > '1' {
+				return "", errorf(b.offsets[i], "invalid digit %q in binary bit string", c)
+			}
+		}
+		bits = b.Value
+	case 'O':
+		expanded, err := b.expand(3, 8)
+		if err != nil {
+			return "", err
+		}
+		bits = expanded
+	case 'X':
+		expanded, err := b.expand(4, 16)
+		if err != nil {
+			return "", err
+		}
+		bits = expanded
+	case 'D':
+		for i := 0; i < len(b.Value); i++ {
+			if !isDecimal(b.Value[i]) {
+				return "", errorf(b.offsets[i], "invalid digit %q in decimal bit string", b.Value[i])
+			}
+		}
+		if b.Value != "" {
+			value, _ := new(big.Int).SetString(b.Value, 10)
+			bits = value.Text(2)
+		}
+	}
+	if b.Length < 0 || b.Length == len(bits) {
+		return bits, nil
+	}
+
+	pad := byte('0')
+	if b.Signed() {
+		if bits == "" {
+			return "", errorf(0, "signed bit string with an empty bit value has no sign to extend")
+		}
+		pad = bits[0]
+	}
+	if b.Length > len(bits) {
+		return strings.Repeat(string(pad), b.Length-len(bits)) + bits, nil
+	}
+	//Truncate, the characters removed must not change the value
+	cut := len(bits) - b.Length
+	if b.Signed() && b.Length > 0 {
+		pad = bits[cut]
+	}
+	for i := 0; i < cut; i++ {
+		if bits[i] != pad {
+			return "", errorf(0, "bit string value does not fit in %d bits", b.Length)
+		}
+	}
+	return bits[cut:], nil
+}
+
+// expand replaces each digit by its bits and replicates any other character.
+func (b BitString) expand(width int, base int) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < len(b.Value); i++ {
+		c := b.Value[i]
+		digit := DigitValue(rune(c))
+		switch {
+		case digit < base:
+			fmt.Fprintf(&sb, "%0*b", width, digit)
+		case isDecimal(c):
+			return "", errorf(b.offsets[i], "invalid digit %q in base %d bit string", c, base)
+		default:
+			sb.WriteString(strings.Repeat(string(c), width))
+		}
+	}
+	return sb.String(), nil
+}
+
+// ExpandBitString returns the string value of a BIT_STR literal, see BitString.Expand.
+func ExpandBitString(lit string) (string, error) {
+	b, err := ParseBitString(lit)
+	if err != nil {
+		return "", err
+	}
+	return b.Expand()
+}
+
+func isDecimal(c byte) bool { return '0' <= c && c <= '9' }
+
+// DigitValue returns the value of an extended digit, 16 if ch is not one.
+func DigitValue(ch rune) int {
+	switch {
+	case '0' <= ch && ch <= '9':
+		return int(ch - '0')
+	case 'a' <= ch && ch <= 'f':
+		return int(ch - 'a' + 10)
+	case 'A' <= ch && ch <= 'F':
+		return int(ch - 'A' + 10)
+	}
+	return 16
+}
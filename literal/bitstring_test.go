@@ -0,0 +1,72 @@
+package literal
+
+import "testing"
+
+func TestExpandBitString(t *testing.T) {
+	tests := []struct {
+		lit  string
+		want string
+	}{
+		{`B"1010"`, "1010"},
+		{`b"1_0_1"`, "101"},
+		{`B""`, ""},
+		{`O"17"`, "001111"},
+		{`X"F0"`, "11110000"},
+		{`x"a_B"`, "10101011"},
+		{`X"Z1"`, "ZZZZ0001"},
+		{`O"-7"`, "---111"},
+		{`B"01XZ"`, "01XZ"},
+		{`12UX"F0"`, "000011110000"},
+		{`12SX"F0"`, "111111110000"},
+		{`12SX"70"`, "000001110000"},
+		{`6X"0F"`, "001111"},
+		{`6SX"F8"`, "111000"},
+		{`3UB"00101"`, "101"},
+		{`12UB"X1"`, "0000000000X1"},
+		{`D"78"`, "1001110"},
+		{`D"0"`, "0"},
+		{`12D"13"`, "000000001101"},
+		{`1_0UB"1"`, "0000000001"},
+	}
+	for _, test := range tests {
+		got, err := ExpandBitString(test.lit)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.lit, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s: got %q, want %q", test.lit, got, test.want)
+		}
+	}
+}
+
+func TestExpandBitStringErrors(t *testing.T) {
+	tests := []struct {
+		lit    string
+		offset int
+	}{
+		{`B"0120"`, 4},
+		{`O"78"`, 3},
+		{`D"1F"`, 3},
+		{`SD"1"`, 0},
+		{`SB"99"`, 3},
+		{`2X"F"`, 0},
+		{`2SX"8"`, 0},
+		{`X"_F"`, 2},
+		{`X"F__0"`, 4},
+		{`X"F_"`, 3},
+		{`4SB""`, 0},
+		{`X"F`, 1},
+	}
+	for _, test := range tests {
+		_, err := ExpandBitString(test.lit)
+		e, ok := err.(*Error)
+		if !ok {
+			t.Errorf("%s: got %v, want a literal error", test.lit, err)
+			continue
+		}
+		if e.Offset != test.offset {
+			t.Errorf("%s: error %q at offset %d, want %d", test.lit, e.Msg, e.Offset, test.offset)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package literal
+
+import "strings"
+
+// PragmaRegion tells whether a pragma starts or ends a region hidden from synthesis.
+type PragmaRegion int
+
+const (
+	NoRegion  PragmaRegion = iota
+	RegionOff              // translate_off, synthesis_off, rtl_synthesis off, vhdl_comp_off, ...
+	RegionOn               // the matching on pragma
+)
+
+// Pragma is a metacomment read by synthesis and other downstream tools
+//
+//	-- synthesis translate_off
+//	-- pragma synthesis_on
+//	-- rtl_synthesis off
+//	-- vhdl_comp_off
+type Pragma struct {
+	Text   string   // comment text
+	Tool   string   // first word in lower case: synthesis, pragma, synopsys, rtl_synthesis, ...
+	Args   []string // the words after the tool word
+	Region PragmaRegion
+}
+
+// pragmaTools are the first words of the recognized metacomments.
+var pragmaTools = map[string]bool{
+	"synthesis":     true,
+	"pragma":        true,
+	"synopsys":      true,
+	"rtl_synthesis": true,
+	"exemplar":      true,
+	"xilinx":        true,
+	"altera":        true,
+	"cadence":       true,
+	"ambit":         true,
+	"vhdl_comp_off": true,
+	"vhdl_comp_on":  true,
+}
+
+// pragmaDirectives are the metacomment directives that do not start or end a region,
+// a comment is only a metacomment if it is a region directive or a list of these.
+var pragmaDirectives = map[string]bool{
+	"full_case":       true,
+	"parallel_case":   true,
+	"dc_script_begin": true,
+	"dc_script_end":   true,
+}
+
+// ParsePragma splits a COMMENT or PRAGMA literal in its words, it reports false
+// if the comment is not a metacomment: its first word is not a tool word or the words
+// after it are not a known directive, as in -- Xilinx specific clock buffer.
+func ParsePragma(comment string) (Pragma, bool) {
+	text := comment
+	switch {
+	case strings.HasPrefix(text, "--"):
+		text = text[2:]
+	case strings.HasPrefix(text, "/*") && strings.HasSuffix(text, "*/") && len(text) >= 4:
+		text = text[2 : len(text)-2]
+	default:
+		return Pragma{}, false
+	}
+	words := strings.Fields(text)
+	if len(words) == 0 || !pragmaTools[strings.ToLower(words[0])] {
+		return Pragma{}, false
+	}
+	p := Pragma{Text: comment, Tool: strings.ToLower(words[0]), Args: words[1:]}
+	directive := strings.ToLower(strings.Join(p.Args, " "))
+	switch {
+	case p.Tool == "vhdl_comp_off":
+		p.Region = RegionOff
+	case p.Tool == "vhdl_comp_on":
+		p.Region = RegionOn
+	case directive == "translate_off" || directive == "synthesis_off":
+		p.Region = RegionOff
+	case directive == "translate_on" || directive == "synthesis_on":
+		p.Region = RegionOn
+	case p.Tool == "rtl_synthesis" && directive == "off" || p.Tool == "ambit" && directive == "synthesis off":
+		p.Region = RegionOff
+	case p.Tool == "rtl_synthesis" && directive == "on" || p.Tool == "ambit" && directive == "synthesis on":
+		p.Region = RegionOn
+	}
+	if p.Region == NoRegion {
+		if len(p.Args) == 0 {
+			return Pragma{}, false
+		}
+		for _, arg := range p.Args {
+			if !pragmaDirectives[strings.ToLower(arg)] {
+				return Pragma{}, false
+			}
+		}
+	}
+	return p, true
+}
@@ -0,0 +1,33 @@
+package literal
+
+import "testing"
+
+func TestParsePragma(t *testing.T) {
+	tests := []struct {
+		comment string
+		ok      bool
+		tool    string
+		region  PragmaRegion
+	}{
+		{"-- synthesis translate_off", true, "synthesis", RegionOff},
+		{"-- Synthesis Translate_On", true, "synthesis", RegionOn},
+		{"--pragma synthesis_off", true, "pragma", RegionOff},
+		{"-- synopsys translate_on", true, "synopsys", RegionOn},
+		{"-- rtl_synthesis off", true, "rtl_synthesis", RegionOff},
+		{"-- rtl_synthesis on", true, "rtl_synthesis", RegionOn},
+		{"-- vhdl_comp_off", true, "vhdl_comp_off", RegionOff},
+		{"/* synthesis translate_on */", true, "synthesis", RegionOn},
+		{"-- synopsys full_case parallel_case", true, "synopsys", NoRegion},
+		{"-- synthesis keep", false, "", NoRegion},
+		{"-- Xilinx specific clock buffer", false, "", NoRegion},
+		{"-- pragma", false, "", NoRegion},
+		{"-- the synthesis result", false, "", NoRegion},
+		{"--", false, "", NoRegion},
+	}
+	for _, test := range tests {
+		p, ok := ParsePragma(test.comment)
+		if ok != test.ok || p.Tool != test.tool || p.Region != test.region {
+			t.Errorf("%s: got %v %q %d, want %v %q %d", test.comment, ok, p.Tool, p.Region, test.ok, test.tool, test.region)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package literal
+
+import "strings"
+
+// Unquote returns the value of a STRING or CHAR literal: the quotation marks are removed
+// and each doubled quotation mark of a string literal stands for one (LRM 15.7).
+//
+//	Unquote(`"He said ""hi"""`) == `He said "hi"`
+//	Unquote(`'''`) == `'`
+func Unquote(lit string) (string, error) {
+	if len(lit) < 2 || lit[0] != lit[len(lit)-1] || lit[0] != '"' && lit[0] != '\'' {
+		return "", errorf(0, "invalid quoted literal %s", lit)
+	}
+	value := lit[1 : len(lit)-1]
+	if lit[0] == '\'' {
+		//A character literal is exactly one graphic character, possibly an apostrophe
+		if n := len([]rune(value)); n != 1 {
+			return "", errorf(0, "character literal %s must contain exactly one character", lit)
+		}
+		return value, nil
+	}
+	for i := 0; i < len(value); i++ {
+		if value[i] == '"' {
+			if i+1 == len(value) || value[i+1] != '"' {
+				return "", errorf(i+1, "quotation mark in string literal must be doubled")
+			}
+			i++
+		}
+	}
+	return strings.ReplaceAll(value, `""`, `"`), nil
+}
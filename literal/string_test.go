@@ -0,0 +1,32 @@
+package literal
+
+import "testing"
+
+func TestUnquote(t *testing.T) {
+	tests := []struct {
+		lit  string
+		want string
+		ok   bool
+	}{
+		{`""`, "", true},
+		{`"abc"`, "abc", true},
+		{`"He said ""hi"""`, `He said "hi"`, true},
+		{`""""`, `"`, true},
+		{`'a'`, "a", true},
+		{`'''`, "'", true},
+		{`'é'`, "é", true},
+		{`'ab'`, "", false},
+		{`"a"b"`, "", false},
+		{`"abc`, "", false},
+	}
+	for _, test := range tests {
+		got, err := Unquote(test.lit)
+		if (err == nil) != test.ok {
+			t.Errorf("%s: got error %v, want ok %v", test.lit, err, test.ok)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s: got %q, want %q", test.lit, got, test.want)
+		}
+	}
+}
@@ -17,7 +17,7 @@ func (p *Parser) parseArchitectureBody() (ast.ArchitectureBody, error) {
 		return architecture, errors.New("Expected ARCHITECTURE keyword")
 	}
 
-	architecture.Identifier.Identifier = p.lit
+	architecture.Identifier = p.identifier()
 	if p.expect(token.IDENT) == token.NoPos {
 		return architecture, errors.New("Expected IDENTIFIER")
 	}
@@ -26,7 +26,7 @@ func (p *Parser) parseArchitectureBody() (ast.ArchitectureBody, error) {
 		return architecture, errors.New("Expected OF keyword")
 	}
 
-	architecture.EntityName = ast.SimpleName{Identifier: ast.Identifier{Identifier: p.lit}}
+	architecture.EntityName = ast.SimpleName{Identifier: p.identifier()}
 	if p.expect(token.IDENT) == token.NoPos {
 		return architecture, errors.New("Expected IDENTIFIER")
 	}
@@ -59,14 +59,15 @@ func (p *Parser) parseArchitectureBody() (ast.ArchitectureBody, error) {
 
 	if p.tok == token.ARCHITECTURE {
 		//Consume the architecture keyword
+		p.checkStandard(p.pos, token.VHDL93, "END ARCHITECTURE")
 		p.next()
 	}
 
 	if p.tok == token.IDENT {
 		//Consume the identifier
-		architecture.ArchitectureSimpleName = &ast.SimpleName{Identifier: ast.Identifier{Identifier: p.lit}}
+		architecture.ArchitectureSimpleName = &ast.SimpleName{Identifier: p.identifier()}
 		p.next()
-		if architecture.Identifier.Identifier != architecture.ArchitectureSimpleName.Identifier.Identifier {
+		if !token.EqualIdent(architecture.Identifier.Identifier, architecture.ArchitectureSimpleName.Identifier.Identifier) {
 			return architecture, errors.New("Architecture identifier does not match")
 		}
 	}
@@ -84,10 +85,11 @@ func (p *Parser) parseArchitectureDeclarativePart() (ast.ArchitectureDeclarative
 	if p.trace {
 		defer un(trace(p, "ArchitectureDeclarativePart"))
 	}
-	//Consume until the begin keyword
-	for p.tok != token.BEGIN {
-		p.next()
+	block_declarative_items, error := p.parseDeclarativePart()
+	if error != nil {
+		return architecture_declarative_part, errors.New("Error parsing block declarative items")
 	}
+	architecture_declarative_part.BlockDeclarativeItems = &block_declarative_items
 	return architecture_declarative_part, nil
 }
 
@@ -96,9 +98,10 @@ func (p *Parser) parseArchitectureStatementPart() (ast.ArchitectureStatementPart
 	if p.trace {
 		defer un(trace(p, "ArchitectureStatementPart"))
 	}
-	//Consume until the end keyword
-	for p.tok != token.END {
-		p.next()
+	concurrent_statements, error := p.parseConcurrentStatements()
+	if error != nil {
+		return architecture_statement_part, errors.New("Error parsing concurrent statements")
 	}
+	architecture_statement_part.ConcurrentStatements = &concurrent_statements
 	return architecture_statement_part, nil
 }
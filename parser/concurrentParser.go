@@ -0,0 +1,729 @@
+package parser
+
+import (
+	"errors"
+	"vhdl/ast"
+	"vhdl/token"
+)
+
+// parseConcurrentStatements parses concurrent statements up to the END of the enclosing
+// architecture, block or generate statement, or up to the next alternative of a generate
+// statement. A statement that can not be parsed is skipped up to its semicolon and the
+// next one is parsed.
+func (p *Parser) parseConcurrentStatements() ([]ast.ConcurrentStatement, error) {
+	var concurrent_statements []ast.ConcurrentStatement
+	if p.trace {
+		defer un(trace(p, "ConcurrentStatements"))
+	}
+
+	for !isStatementsEnd(p.tok) {
+		concurrent_statement, error := p.parseConcurrentStatement()
+		if error != nil {
+			p.skipTo(token.SEMICOLON, token.END)
+			if p.tok == token.SEMICOLON {
+				p.next()
+			}
+			continue
+		}
+		concurrent_statements = append(concurrent_statements, concurrent_statement)
+	}
+	return concurrent_statements, nil
+}
+
+// isStatementsEnd reports whether tok ends a list of concurrent or sequential statements.
+func isStatementsEnd(tok token.Token) bool {
+	switch tok {
+	case token.END, token.ELSIF, token.ELSE, token.WHEN, token.EOF:
+		return true
+	}
+	return false
+}
+
+func (p *Parser) parseConcurrentStatement() (ast.ConcurrentStatement, error) {
+	var concurrent_statement ast.ConcurrentStatement
+	if p.trace {
+		defer un(trace(p, "ConcurrentStatement"))
+	}
+	pos := p.pos
+
+	var label *ast.Identifier
+	if p.tok == token.IDENT && p.tok2 == token.COLON {
+		identifier := p.identifier()
+		label = &identifier
+		p.next()
+		p.next()
+	}
+	postponed, postponed_pos := p.tok == token.POSTPONED, p.pos
+	if postponed {
+		p.next()
+	}
+
+	switch p.tok {
+	case token.PROCESS:
+		return p.parseProcessStatement(label, postponed, pos)
+	case token.ASSERT:
+		assertion_statement := ast.ConcurrentAssertionStatement{Label: label, Postponed: postponed, Node: ast.Node{Pos: pos}}
+		assertion, error := p.parseAssertion()
+		if error != nil {
+			return assertion_statement, errors.New("invalid assertion")
+		}
+		assertion_statement.Assertion = assertion
+		if p.expect(token.SEMICOLON) == token.NoPos {
+			return assertion_statement, errors.New("invalid concurrent assertion statement")
+		}
+		assertion_statement.End = p.prevEnd
+		return assertion_statement, nil
+	case token.WITH:
+		return p.parseConcurrentSelectedSignalAssignment(label, postponed, pos)
+	case token.IDENT:
+		return p.parseConcurrentNameStatement(label, postponed, postponed_pos, pos)
+	case token.LPAREN:
+		target, error := p.parseTarget()
+		if error != nil {
+			return concurrent_statement, errors.New("invalid target")
+		}
+		return p.parseConcurrentSignalAssignment(label, postponed, target, pos)
+	case token.BLOCK, token.FOR, token.IF, token.CASE, token.COMPONENT, token.ENTITY, token.CONFIGURATION:
+	default:
+		p.errorExpected(p.pos, "expected concurrent statement, found %s", p.tok)
+		return concurrent_statement, errors.New("invalid concurrent statement")
+	}
+
+	//Blocks, generate statements and instantiations are always labeled and never postponed
+	if label == nil {
+		p.errorExpected(p.pos, "expected label before %s", p.tok)
+	}
+	if postponed {
+		p.error(postponed_pos, "%s can not be postponed", p.tok)
+	}
+	switch p.tok {
+	case token.BLOCK:
+		return p.parseBlockStatement(label, pos)
+	case token.FOR:
+		return p.parseForGenerateStatement(label, pos)
+	case token.IF:
+		return p.parseIfGenerateStatement(label, pos)
+	case token.CASE:
+		return p.parseCaseGenerateStatement(label, pos)
+	}
+	instantiated_unit, error := p.parseInstantiatedUnit()
+	if error != nil {
+		return concurrent_statement, errors.New("invalid instantiated unit")
+	}
+	return p.parseComponentInstantiationStatement(label, instantiated_unit, pos)
+}
+
+// parseTarget parses the target of an assignment, a name or an aggregate.
+func (p *Parser) parseTarget() (ast.Name, error) {
+	if p.tok == token.LPAREN {
+		return p.parseAggregateOrParenExpr()
+	}
+	return p.parseName()
+}
+
+// parseConcurrentNameStatement parses the statements that start with a name: a signal
+// assignment to the name, an instantiation of the component it names or a call to the
+// procedure it names. A name followed by a semicolon is parsed as a procedure call, it
+// can also be the instantiation of a component without generics and ports.
+func (p *Parser) parseConcurrentNameStatement(label *ast.Identifier, postponed bool, postponed_pos, pos token.Pos) (ast.ConcurrentStatement, error) {
+	var concurrent_statement ast.ConcurrentStatement
+	name_pos := p.pos
+	name, error := p.parseName()
+	if error != nil {
+		return concurrent_statement, errors.New("invalid name")
+	}
+
+	switch p.tok {
+	case token.LEQ_SA:
+		return p.parseConcurrentSignalAssignment(label, postponed, name, pos)
+	case token.GENERIC, token.PORT:
+		if label == nil {
+			p.errorExpected(name_pos, "expected label before component instantiation")
+		}
+		if postponed {
+			p.error(postponed_pos, "component instantiation can not be postponed")
+		}
+		instantiated_component := ast.InstantiatedComponent{ComponentName: name, Node: ast.Node{Pos: name_pos, End: p.prevEnd}}
+		return p.parseComponentInstantiationStatement(label, instantiated_component, pos)
+	}
+
+	procedure_call_statement := ast.ConcurrentProcedureCallStatement{Label: label, Postponed: postponed, Node: ast.Node{Pos: pos}}
+	procedure_call_statement.ProcedureCall = ast.ProcedureCall{Name: name, Node: ast.Node{Pos: name_pos, End: p.prevEnd}}
+	if p.expect(token.SEMICOLON) == token.NoPos {
+		return procedure_call_statement, errors.New("invalid concurrent procedure call")
+	}
+	procedure_call_statement.End = p.prevEnd
+	return procedure_call_statement, nil
+}
+
+// parseProcessStatement parses a process statement from the PROCESS keyword, the label
+// and POSTPONED are already parsed.
+func (p *Parser) parseProcessStatement(label *ast.Identifier, postponed bool, pos token.Pos) (ast.ProcessStatement, error) {
+	process := ast.ProcessStatement{Label: label, Postponed: postponed, Node: ast.Node{Pos: pos}}
+	if p.trace {
+		defer un(trace(p, "ProcessStatement"))
+	}
+	if p.expect(token.PROCESS) == token.NoPos {
+		return process, errors.New("invalid process statement")
+	}
+
+	if p.tok == token.LPAREN {
+		p.next()
+		if p.tok == token.ALL {
+			p.checkStandard(p.pos, token.VHDL2008, "PROCESS (ALL)")
+			process.All = true
+			p.next()
+		} else {
+			for {
+				name, error := p.parseName()
+				if error != nil {
+					return process, errors.New("invalid sensitivity list")
+				}
+				process.SensitivityList = append(process.SensitivityList, name)
+				if p.tok != token.COMMA {
+					break
+				}
+				p.next()
+			}
+		}
+		if p.expect(token.RPAREN) == token.NoPos {
+			return process, errors.New("invalid sensitivity list")
+		}
+	}
+	if p.tok == token.IS {
+		p.checkStandard(p.pos, token.VHDL93, "IS in a process statement")
+		p.next()
+	}
+
+	declarative_items, error := p.parseDeclarativePart()
+	if error != nil {
+		return process, errors.New("invalid process declarative part")
+	}
+	process.ProcessDeclarativeItems = declarative_items
+
+	if p.expect(token.BEGIN) == token.NoPos {
+		return process, errors.New("invalid process statement")
+	}
+	sequential_statements, error := p.parseSequenceOfStatements()
+	if error != nil {
+		return process, errors.New("invalid process statement part")
+	}
+	process.SequentialStatements = sequential_statements
+
+	if p.expect(token.END) == token.NoPos {
+		return process, errors.New("invalid process statement")
+	}
+	if p.tok == token.POSTPONED {
+		if !postponed {
+			p.error(p.pos, "END POSTPONED PROCESS closes a process that is not postponed")
+		}
+		p.next()
+	}
+	if p.expect(token.PROCESS) == token.NoPos {
+		return process, errors.New("invalid process statement")
+	}
+	process.ProcessLabel = p.parseClosingLabel(label)
+	if p.expect(token.SEMICOLON) == token.NoPos {
+		return process, errors.New("invalid process statement")
+	}
+
+	process.End = p.prevEnd
+	return process, nil
+}
+
+// parseConcurrentSignalAssignment parses a simple or a conditional signal assignment to
+// target, from the <= delimiter.
+func (p *Parser) parseConcurrentSignalAssignment(label *ast.Identifier, postponed bool, target ast.Name, pos token.Pos) (ast.ConcurrentStatement, error) {
+	var concurrent_statement ast.ConcurrentStatement
+	if p.trace {
+		defer un(trace(p, "ConcurrentSignalAssignment"))
+	}
+	if p.expect(token.LEQ_SA) == token.NoPos {
+		return concurrent_statement, errors.New("invalid signal assignment")
+	}
+
+	guarded := p.tok == token.GUARDED
+	if guarded {
+		p.next()
+	}
+	delay_mechanism, error := p.parseDelayMechanism()
+	if error != nil {
+		return concurrent_statement, errors.New("invalid delay mechanism")
+	}
+	waveform, error := p.parseWaveform()
+	if error != nil {
+		return concurrent_statement, errors.New("invalid waveform")
+	}
+
+	if p.tok != token.WHEN {
+		simple_assignment := ast.ConcurrentSimpleSignalAssignment{Label: label, Postponed: postponed, Target: target, Guarded: guarded, DelayMechanism: delay_mechanism, Waveform: waveform, Node: ast.Node{Pos: pos}}
+		if p.expect(token.SEMICOLON) == token.NoPos {
+			return simple_assignment, errors.New("invalid signal assignment")
+		}
+		simple_assignment.End = p.prevEnd
+		return simple_assignment, nil
+	}
+
+	conditional_assignment := ast.ConcurrentConditionalSignalAssignment{Label: label, Postponed: postponed, Target: target, Guarded: guarded, DelayMechanism: delay_mechanism, Node: ast.Node{Pos: pos}}
+	conditional_waveforms, error := p.parseConditionalWaveforms(waveform)
+	if error != nil {
+		return conditional_assignment, errors.New("invalid conditional waveforms")
+	}
+	conditional_assignment.ConditionalWaveforms = conditional_waveforms
+	if p.expect(token.SEMICOLON) == token.NoPos {
+		return conditional_assignment, errors.New("invalid signal assignment")
+	}
+
+	conditional_assignment.End = p.prevEnd
+	return conditional_assignment, nil
+}
+
+// parseConcurrentSelectedSignalAssignment parses a selected signal assignment from the
+// WITH keyword.
+func (p *Parser) parseConcurrentSelectedSignalAssignment(label *ast.Identifier, postponed bool, pos token.Pos) (ast.ConcurrentSelectedSignalAssignment, error) {
+	selected_assignment := ast.ConcurrentSelectedSignalAssignment{Label: label, Postponed: postponed, Node: ast.Node{Pos: pos}}
+	if p.trace {
+		defer un(trace(p, "ConcurrentSelectedSignalAssignment"))
+	}
+	if p.expect(token.WITH) == token.NoPos {
+		return selected_assignment, errors.New("invalid selected signal assignment")
+	}
+
+	expression, error := p.parseExpression()
+	if error != nil {
+		return selected_assignment, errors.New("invalid expression")
+	}
+	selected_assignment.Expression = expression
+	if p.expect(token.SELECT) == token.NoPos {
+		return selected_assignment, errors.New("invalid selected signal assignment")
+	}
+	if p.tok == token.QUEST {
+		//The scanner reports ? before VHDL-2008
+		selected_assignment.Matching = true
+		p.next()
+	}
+
+	target, error := p.parseTarget()
+	if error != nil {
+		return selected_assignment, errors.New("invalid target")
+	}
+	selected_assignment.Target = target
+	if p.expect(token.LEQ_SA) == token.NoPos {
+		return selected_assignment, errors.New("invalid selected signal assignment")
+	}
+
+	if p.tok == token.GUARDED {
+		selected_assignment.Guarded = true
+		p.next()
+	}
+	delay_mechanism, error := p.parseDelayMechanism()
+	if error != nil {
+		return selected_assignment, errors.New("invalid delay mechanism")
+	}
+	selected_assignment.DelayMechanism = delay_mechanism
+
+	selected_waveforms, error := p.parseSelectedWaveforms()
+	if error != nil {
+		return selected_assignment, errors.New("invalid selected waveforms")
+	}
+	selected_assignment.SelectedWaveforms = selected_waveforms
+	if p.expect(token.SEMICOLON) == token.NoPos {
+		return selected_assignment, errors.New("invalid selected signal assignment")
+	}
+
+	selected_assignment.End = p.prevEnd
+	return selected_assignment, nil
+}
+
+// parseInstantiatedUnit parses component component_name, entity entity_name
+// [ ( architecture_identifier ) ] or configuration configuration_name.
+func (p *Parser) parseInstantiatedUnit() (ast.InstantiatedUnit, error) {
+	var instantiated_unit ast.InstantiatedUnit
+	pos := p.pos
+	switch p.tok {
+	case token.ENTITY, token.CONFIGURATION:
+		p.checkStandard(p.pos, token.VHDL93, "direct instantiation")
+		return p.parseEntityAspect()
+	case token.COMPONENT:
+		p.checkStandard(p.pos, token.VHDL93, "COMPONENT in a component instantiation")
+		p.next()
+	default:
+		p.errorExpected(p.pos, "expected COMPONENT, ENTITY or CONFIGURATION, found %s", p.tok)
+		return instantiated_unit, errors.New("invalid instantiated unit")
+	}
+
+	component_name, error := p.parseName()
+	if error != nil {
+		return instantiated_unit, errors.New("invalid component name")
+	}
+	return ast.InstantiatedComponent{ComponentName: component_name, Node: ast.Node{Pos: pos, End: p.prevEnd}}, nil
+}
+
+// parseComponentInstantiationStatement parses the generic and port map aspects that
+// follow the instantiated unit.
+func (p *Parser) parseComponentInstantiationStatement(label *ast.Identifier, instantiated_unit ast.InstantiatedUnit, pos token.Pos) (ast.ComponentInstantiationStatement, error) {
+	instantiation := ast.ComponentInstantiationStatement{Label: label, InstantiatedUnit: instantiated_unit, Node: ast.Node{Pos: pos}}
+	if p.trace {
+		defer un(trace(p, "ComponentInstantiationStatement"))
+	}
+
+	if p.tok == token.GENERIC {
+		generic_map_aspect, error := p.parseGenericMapAspect(false)
+		if error != nil {
+			return instantiation, errors.New("invalid generic map aspect")
+		}
+		instantiation.GenericMapAspect = &generic_map_aspect
+	}
+	if p.tok == token.PORT {
+		port_map_aspect, error := p.parsePortMapAspect()
+		if error != nil {
+			return instantiation, errors.New("invalid port map aspect")
+		}
+		instantiation.PortMapAspect = &port_map_aspect
+	}
+	if p.expect(token.SEMICOLON) == token.NoPos {
+		return instantiation, errors.New("invalid component instantiation")
+	}
+
+	instantiation.End = p.prevEnd
+	return instantiation, nil
+}
+
+// parseBlockStatement parses a block statement from the BLOCK keyword.
+func (p *Parser) parseBlockStatement(label *ast.Identifier, pos token.Pos) (ast.BlockStatement, error) {
+	block := ast.BlockStatement{Label: label, Node: ast.Node{Pos: pos}}
+	if p.trace {
+		defer un(trace(p, "BlockStatement"))
+	}
+	if p.expect(token.BLOCK) == token.NoPos {
+		return block, errors.New("invalid block statement")
+	}
+
+	if p.tok == token.LPAREN {
+		p.next()
+		guard_condition, error := p.parseExpression()
+		if error != nil {
+			return block, errors.New("invalid guard condition")
+		}
+		block.GuardCondition = guard_condition
+		if p.expect(token.RPAREN) == token.NoPos {
+			return block, errors.New("invalid guard condition")
+		}
+	}
+	if p.tok == token.IS {
+		p.checkStandard(p.pos, token.VHDL93, "IS in a block statement")
+		p.next()
+	}
+
+	block_header, error := p.parseBlockHeader()
+	if error != nil {
+		return block, errors.New("invalid block header")
+	}
+	block.BlockHeader = block_header
+
+	declarative_items, error := p.parseDeclarativePart()
+	if error != nil {
+		return block, errors.New("invalid block declarative part")
+	}
+	block.BlockDeclarativeItems = declarative_items
+
+	if p.expect(token.BEGIN) == token.NoPos {
+		return block, errors.New("invalid block statement")
+	}
+	concurrent_statements, error := p.parseConcurrentStatements()
+	if error != nil {
+		return block, errors.New("invalid block statement part")
+	}
+	block.ConcurrentStatements = concurrent_statements
+
+	if p.expect(token.END) == token.NoPos || p.expect(token.BLOCK) == token.NoPos {
+		return block, errors.New("invalid block statement")
+	}
+	block.BlockLabel = p.parseClosingLabel(label)
+	if p.expect(token.SEMICOLON) == token.NoPos {
+		return block, errors.New("invalid block statement")
+	}
+
+	block.End = p.prevEnd
+	return block, nil
+}
+
+// parseBlockHeader parses the generic and port clauses of a block and their map aspects,
+// the header is empty if there are none.
+func (p *Parser) parseBlockHeader() (ast.BlockHeader, error) {
+	block_header := ast.BlockHeader{}
+	pos := p.pos
+
+	if p.tok == token.GENERIC {
+		generic_clause, error := p.parseFormalGenericClause()
+		if error != nil {
+			return block_header, errors.New("invalid generic clause")
+		}
+		block_header.GenericClause = &generic_clause
+		if p.tok == token.GENERIC {
+			generic_map_aspect, error := p.parseGenericMapAspect(false)
+			if error != nil {
+				return block_header, errors.New("invalid generic map aspect")
+			}
+			block_header.GenericMapAspect = &generic_map_aspect
+			if p.expect(token.SEMICOLON) == token.NoPos {
+				return block_header, errors.New("invalid block header")
+			}
+		}
+	}
+	if p.tok == token.PORT {
+		port_clause, error := p.parseFormalPortClause()
+		if error != nil {
+			return block_header, errors.New("invalid port clause")
+		}
+		block_header.PortClause = &port_clause
+		if p.tok == token.PORT {
+			port_map_aspect, error := p.parsePortMapAspect()
+			if error != nil {
+				return block_header, errors.New("invalid port map aspect")
+			}
+			block_header.PortMapAspect = &port_map_aspect
+			if p.expect(token.SEMICOLON) == token.NoPos {
+				return block_header, errors.New("invalid block header")
+			}
+		}
+	}
+
+	if block_header.GenericClause != nil || block_header.PortClause != nil {
+		block_header.Node = ast.Node{Pos: pos, End: p.prevEnd}
+	}
+	return block_header, nil
+}
+
+// parseForGenerateStatement parses a for generate statement from the FOR keyword.
+func (p *Parser) parseForGenerateStatement(label *ast.Identifier, pos token.Pos) (ast.ForGenerateStatement, error) {
+	for_generate := ast.ForGenerateStatement{Label: label, Node: ast.Node{Pos: pos}}
+	if p.trace {
+		defer un(trace(p, "ForGenerateStatement"))
+	}
+	if p.expect(token.FOR) == token.NoPos {
+		return for_generate, errors.New("invalid for generate statement")
+	}
+
+	parameter_specification, error := p.parseParameterSpecification()
+	if error != nil {
+		return for_generate, errors.New("invalid parameter specification")
+	}
+	for_generate.ParameterSpecification = parameter_specification
+	if p.expect(token.GENERATE) == token.NoPos {
+		return for_generate, errors.New("invalid for generate statement")
+	}
+
+	generate_statement_body, error := p.parseGenerateStatementBody(nil)
+	if error != nil {
+		return for_generate, errors.New("invalid generate statement body")
+	}
+	for_generate.GenerateStatementBody = generate_statement_body
+
+	generate_label, error := p.parseEndGenerate(label)
+	if error != nil {
+		return for_generate, error
+	}
+	for_generate.GenerateLabel = generate_label
+
+	for_generate.End = p.prevEnd
+	return for_generate, nil
+}
+
+// parseIfGenerateStatement parses an if generate statement from the IF keyword, the
+// ELSIF and ELSE alternatives are VHDL-2008.
+func (p *Parser) parseIfGenerateStatement(label *ast.Identifier, pos token.Pos) (ast.IfGenerateStatement, error) {
+	if_generate := ast.IfGenerateStatement{Label: label, Node: ast.Node{Pos: pos}}
+	if p.trace {
+		defer un(trace(p, "IfGenerateStatement"))
+	}
+	if p.expect(token.IF) == token.NoPos {
+		return if_generate, errors.New("invalid if generate statement")
+	}
+
+	for {
+		alternative := ast.IfGenerateAlternative{Node: ast.Node{Pos: p.pos}}
+		alternative.AlternativeLabel = p.parseAlternativeLabel()
+		condition, error := p.parseExpression()
+		if error != nil {
+			return if_generate, errors.New("invalid condition")
+		}
+		alternative.Condition = condition
+		if p.expect(token.GENERATE) == token.NoPos {
+			return if_generate, errors.New("invalid if generate statement")
+		}
+		generate_statement_body, error := p.parseGenerateStatementBody(alternative.AlternativeLabel)
+		if error != nil {
+			return if_generate, errors.New("invalid generate statement body")
+		}
+		alternative.GenerateStatementBody = generate_statement_body
+		alternative.End = p.prevEnd
+		if_generate.Alternatives = append(if_generate.Alternatives, alternative)
+
+		if p.tok != token.ELSIF {
+			break
+		}
+		p.checkStandard(p.pos, token.VHDL2008, "ELSIF in a generate statement")
+		p.next()
+	}
+
+	if p.tok == token.ELSE {
+		p.checkStandard(p.pos, token.VHDL2008, "ELSE in a generate statement")
+		p.next()
+		alternative := ast.IfGenerateAlternative{Node: ast.Node{Pos: p.pos}}
+		alternative.AlternativeLabel = p.parseAlternativeLabel()
+		if p.expect(token.GENERATE) == token.NoPos {
+			return if_generate, errors.New("invalid if generate statement")
+		}
+		generate_statement_body, error := p.parseGenerateStatementBody(alternative.AlternativeLabel)
+		if error != nil {
+			return if_generate, errors.New("invalid generate statement body")
+		}
+		alternative.GenerateStatementBody = generate_statement_body
+		alternative.End = p.prevEnd
+		if_generate.Alternatives = append(if_generate.Alternatives, alternative)
+	}
+
+	generate_label, error := p.parseEndGenerate(label)
+	if error != nil {
+		return if_generate, error
+	}
+	if_generate.GenerateLabel = generate_label
+
+	if_generate.End = p.prevEnd
+	return if_generate, nil
+}
+
+// parseCaseGenerateStatement parses a VHDL-2008 case generate statement from the CASE
+// keyword.
+func (p *Parser) parseCaseGenerateStatement(label *ast.Identifier, pos token.Pos) (ast.CaseGenerateStatement, error) {
+	case_generate := ast.CaseGenerateStatement{Label: label, Node: ast.Node{Pos: pos}}
+	if p.trace {
+		defer un(trace(p, "CaseGenerateStatement"))
+	}
+	p.checkStandard(p.pos, token.VHDL2008, "CASE generate statement")
+	if p.expect(token.CASE) == token.NoPos {
+		return case_generate, errors.New("invalid case generate statement")
+	}
+
+	expression, error := p.parseExpression()
+	if error != nil {
+		return case_generate, errors.New("invalid expression")
+	}
+	case_generate.Expression = expression
+	if p.expect(token.GENERATE) == token.NoPos {
+		return case_generate, errors.New("invalid case generate statement")
+	}
+
+	if p.tok != token.WHEN {
+		p.errorExpected(p.pos, "expected WHEN, found %s", p.tok)
+		return case_generate, errors.New("invalid case generate statement")
+	}
+	for p.tok == token.WHEN {
+		alternative := ast.CaseGenerateAlternative{Node: ast.Node{Pos: p.pos}}
+		p.next()
+		alternative.AlternativeLabel = p.parseAlternativeLabel()
+		choices, error := p.parseChoices()
+		if error != nil {
+			return case_generate, errors.New("invalid choices")
+		}
+		alternative.Choices = choices
+		if p.expect(token.ARROW) == token.NoPos {
+			return case_generate, errors.New("invalid case generate alternative")
+		}
+		generate_statement_body, error := p.parseGenerateStatementBody(alternative.AlternativeLabel)
+		if error != nil {
+			return case_generate, errors.New("invalid generate statement body")
+		}
+		alternative.GenerateStatementBody = generate_statement_body
+		alternative.End = p.prevEnd
+		case_generate.Alternatives = append(case_generate.Alternatives, alternative)
+	}
+
+	generate_label, error := p.parseEndGenerate(label)
+	if error != nil {
+		return case_generate, error
+	}
+	case_generate.GenerateLabel = generate_label
+
+	case_generate.End = p.prevEnd
+	return case_generate, nil
+}
+
+// parseGenerateStatementBody parses the declarations and the statements of a generate
+// statement, or of one of its alternatives. The body of an alternative can be closed by
+// end [ alternative_label ] ; since VHDL-2008.
+func (p *Parser) parseGenerateStatementBody(alternative_label *ast.Identifier) (ast.GenerateStatementBody, error) {
+	generate_statement_body := ast.GenerateStatementBody{Node: ast.Node{Pos: p.pos}}
+	if p.trace {
+		defer un(trace(p, "GenerateStatementBody"))
+	}
+
+	if p.tok == token.BEGIN || isDeclarationStart(p.tok) {
+		declarative_items, error := p.parseDeclarativePart()
+		if error != nil {
+			return generate_statement_body, errors.New("invalid generate declarative part")
+		}
+		generate_statement_body.BlockDeclarativeItems = declarative_items
+		if p.expect(token.BEGIN) == token.NoPos {
+			return generate_statement_body, errors.New("invalid generate statement body")
+		}
+	}
+
+	concurrent_statements, error := p.parseConcurrentStatements()
+	if error != nil {
+		return generate_statement_body, errors.New("invalid generate statement part")
+	}
+	generate_statement_body.ConcurrentStatements = concurrent_statements
+
+	if p.tok == token.END && p.tok2 != token.GENERATE {
+		p.checkStandard(p.pos, token.VHDL2008, "END of a generate alternative")
+		p.next()
+		generate_statement_body.AlternativeLabel = p.parseClosingLabel(alternative_label)
+		if p.expect(token.SEMICOLON) == token.NoPos {
+			return generate_statement_body, errors.New("invalid generate statement body")
+		}
+	}
+
+	generate_statement_body.End = p.prevEnd
+	return generate_statement_body, nil
+}
+
+// parseEndGenerate parses end generate [ label ] ; and returns the closing label.
+func (p *Parser) parseEndGenerate(label *ast.Identifier) (*ast.SimpleName, error) {
+	if p.expect(token.END) == token.NoPos || p.expect(token.GENERATE) == token.NoPos {
+		return nil, errors.New("invalid generate statement")
+	}
+	generate_label := p.parseClosingLabel(label)
+	if p.expect(token.SEMICOLON) == token.NoPos {
+		return generate_label, errors.New("invalid generate statement")
+	}
+	return generate_label, nil
+}
+
+// parseAlternativeLabel parses the optional label of a VHDL-2008 generate alternative.
+func (p *Parser) parseAlternativeLabel() *ast.Identifier {
+	if p.tok != token.IDENT || p.tok2 != token.COLON {
+		return nil
+	}
+	p.checkStandard(p.pos, token.VHDL2008, "alternative label")
+	identifier := p.identifier()
+	p.next()
+	p.next()
+	return &identifier
+}
+
+// parseClosingLabel parses the optional label that closes the statement labeled label,
+// it reports an error if the statement has no label or a different one.
+func (p *Parser) parseClosingLabel(label *ast.Identifier) *ast.SimpleName {
+	if label != nil {
+		return p.parseClosingSimpleName(*label)
+	}
+	if p.tok == token.IDENT {
+		p.error(p.pos, "closing label %s of a statement without label", p.lit)
+		simple_name := &ast.SimpleName{Identifier: p.identifier()}
+		p.next()
+		return simple_name
+	}
+	return nil
+}
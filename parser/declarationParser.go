@@ -0,0 +1,866 @@
+package parser
+
+import (
+	"errors"
+	"vhdl/ast"
+	"vhdl/token"
+)
+
+// parseDeclarativePart parses declarative items up to BEGIN or END. A declaration that
+// can not be parsed is skipped up to its semicolon and the next one is parsed.
+func (p *Parser) parseDeclarativePart() ([]ast.BlockDeclarativeItem, error) {
+	var declarative_items []ast.BlockDeclarativeItem
+	if p.trace {
+		defer un(trace(p, "DeclarativePart"))
+	}
+
+	for p.tok != token.BEGIN && p.tok != token.END && p.tok != token.EOF {
+		declarative_item, error := p.parseBlockDeclarativeItem()
+		if error != nil {
+			p.skipTo(token.SEMICOLON, token.BEGIN, token.END)
+			if p.tok == token.SEMICOLON {
+				p.next()
+			}
+			continue
+		}
+		declarative_items = append(declarative_items, declarative_item)
+	}
+	return declarative_items, nil
+}
+
+func (p *Parser) parseBlockDeclarativeItem() (ast.BlockDeclarativeItem, error) {
+	var declarative_item ast.BlockDeclarativeItem
+	if p.trace {
+		defer un(trace(p, "BlockDeclarativeItem"))
+	}
+
+	switch p.tok {
+	case token.SIGNAL:
+		return p.parseSignalDeclaration()
+	case token.CONSTANT:
+		return p.parseConstantDeclaration()
+	case token.VARIABLE, token.SHARED:
+		return p.parseVariableDeclaration()
+	case token.FILE:
+		return p.parseFileDeclaration()
+	case token.TYPE:
+		return p.parseTypeDeclaration()
+	case token.SUBTYPE:
+		return p.parseSubtypeDeclaration()
+	case token.COMPONENT:
+		return p.parseComponentDeclaration()
+	case token.ALIAS:
+		return p.parseAliasDeclaration()
+	case token.ATTRIBUTE:
+		return p.parseAttributeDeclarationOrSpecification()
+	case token.PROCEDURE, token.FUNCTION, token.PURE, token.IMPURE:
+		return p.parseSubprogramDeclarationOrBody()
+	case token.FOR:
+		return p.parseConfigurationSpecification()
+	case token.DISCONNECT:
+		return p.parseDisconnectionSpecification()
+	case token.USE:
+		return p.parseUseClause()
+	case token.GROUP:
+		return p.parseGroupTemplateOrDeclaration()
+	case token.PACKAGE:
+		return p.parsePackageInstantiationDeclaration()
+	default:
+		p.errorExpected(p.pos, "expected declaration, found %s", p.tok)
+	}
+	return declarative_item, errors.New("invalid declarative item")
+}
+
+// isDeclarationStart reports whether tok starts a block declarative item.
+func isDeclarationStart(tok token.Token) bool {
+	switch tok {
+	case token.SIGNAL, token.CONSTANT, token.VARIABLE, token.SHARED, token.FILE, token.TYPE, token.SUBTYPE,
+		token.COMPONENT, token.ALIAS, token.ATTRIBUTE, token.PROCEDURE, token.FUNCTION, token.PURE, token.IMPURE,
+		token.FOR, token.DISCONNECT, token.USE, token.GROUP, token.PACKAGE:
+		return true
+	}
+	return false
+}
+
+func (p *Parser) parseSignalDeclaration() (ast.SignalDeclaration, error) {
+	signal_declaration := ast.SignalDeclaration{Node: ast.Node{Pos: p.pos}}
+	if p.trace {
+		defer un(trace(p, "SignalDeclaration"))
+	}
+	if p.expect(token.SIGNAL) == token.NoPos {
+		return signal_declaration, errors.New("invalid signal declaration")
+	}
+
+	identifier_list, error := p.parseIdentifierList()
+	if error != nil {
+		return signal_declaration, errors.New("invalid identifier list")
+	}
+	signal_declaration.IdentifierList = identifier_list
+
+	if p.expect(token.COLON) == token.NoPos {
+		return signal_declaration, errors.New("invalid signal declaration")
+	}
+
+	subtype_indication, error := p.parseSubtypeIndication()
+	if error != nil {
+		return signal_declaration, errors.New("invalid subtype indication")
+	}
+	signal_declaration.SubtypeIndication = subtype_indication
+
+	signal_declaration.SignalKind = token.ILLEGAL
+	if p.tok == token.REGISTER || p.tok == token.BUS {
+		signal_declaration.SignalKind = p.tok
+		p.next()
+	}
+
+	if p.tok == token.VAR_ASSIGN {
+		p.next()
+		expression, error := p.parseExpression()
+		if error != nil {
+			return signal_declaration, errors.New("invalid expression")
+		}
+		signal_declaration.Expression = expression
+	}
+
+	if p.expect(token.SEMICOLON) == token.NoPos {
+		return signal_declaration, errors.New("invalid signal declaration")
+	}
+	signal_declaration.End = p.prevEnd
+	return signal_declaration, nil
+}
+
+func (p *Parser) parseConstantDeclaration() (ast.ConstantDeclaration, error) {
+	constant_declaration := ast.ConstantDeclaration{Node: ast.Node{Pos: p.pos}}
+	if p.trace {
+		defer un(trace(p, "ConstantDeclaration"))
+	}
+	if p.expect(token.CONSTANT) == token.NoPos {
+		return constant_declaration, errors.New("invalid constant declaration")
+	}
+
+	identifier_list, error := p.parseIdentifierList()
+	if error != nil {
+		return constant_declaration, errors.New("invalid identifier list")
+	}
+	constant_declaration.IdentifierList = identifier_list
+
+	if p.expect(token.COLON) == token.NoPos {
+		return constant_declaration, errors.New("invalid constant declaration")
+	}
+
+	subtype_indication, error := p.parseSubtypeIndication()
+	if error != nil {
+		return constant_declaration, errors.New("invalid subtype indication")
+	}
+	constant_declaration.SubtypeIndication = subtype_indication
+
+	if p.tok == token.VAR_ASSIGN {
+		p.next()
+		expression, error := p.parseExpression()
+		if error != nil {
+			return constant_declaration, errors.New("invalid expression")
+		}
+		constant_declaration.Expression = expression
+	}
+
+	if p.expect(token.SEMICOLON) == token.NoPos {
+		return constant_declaration, errors.New("invalid constant declaration")
+	}
+	constant_declaration.End = p.prevEnd
+	return constant_declaration, nil
+}
+
+func (p *Parser) parseVariableDeclaration() (ast.VariableDeclaration, error) {
+	variable_declaration := ast.VariableDeclaration{Node: ast.Node{Pos: p.pos}}
+	if p.trace {
+		defer un(trace(p, "VariableDeclaration"))
+	}
+	if p.tok == token.SHARED {
+		variable_declaration.Shared = true
+		p.next()
+	}
+	if p.expect(token.VARIABLE) == token.NoPos {
+		return variable_declaration, errors.New("invalid variable declaration")
+	}
+
+	identifier_list, error := p.parseIdentifierList()
+	if error != nil {
+		return variable_declaration, errors.New("invalid identifier list")
+	}
+	variable_declaration.IdentifierList = identifier_list
+
+	if p.expect(token.COLON) == token.NoPos {
+		return variable_declaration, errors.New("invalid variable declaration")
+	}
+
+	subtype_indication, error := p.parseSubtypeIndication()
+	if error != nil {
+		return variable_declaration, errors.New("invalid subtype indication")
+	}
+	variable_declaration.SubtypeIndication = subtype_indication
+
+	if p.tok == token.VAR_ASSIGN {
+		p.next()
+		expression, error := p.parseExpression()
+		if error != nil {
+			return variable_declaration, errors.New("invalid expression")
+		}
+		variable_declaration.Expression = expression
+	}
+
+	if p.expect(token.SEMICOLON) == token.NoPos {
+		return variable_declaration, errors.New("invalid variable declaration")
+	}
+	variable_declaration.End = p.prevEnd
+	return variable_declaration, nil
+}
+
+func (p *Parser) parseFileDeclaration() (ast.FileDeclaration, error) {
+	file_declaration := ast.FileDeclaration{Node: ast.Node{Pos: p.pos}}
+	if p.trace {
+		defer un(trace(p, "FileDeclaration"))
+	}
+	if p.expect(token.FILE) == token.NoPos {
+		return file_declaration, errors.New("invalid file declaration")
+	}
+
+	identifier_list, error := p.parseIdentifierList()
+	if error != nil {
+		return file_declaration, errors.New("invalid identifier list")
+	}
+	file_declaration.IdentifierList = identifier_list
+
+	if p.expect(token.COLON) == token.NoPos {
+		return file_declaration, errors.New("invalid file declaration")
+	}
+
+	subtype_indication, error := p.parseSubtypeIndication()
+	if error != nil {
+		return file_declaration, errors.New("invalid subtype indication")
+	}
+	file_declaration.SubtypeIndication = subtype_indication
+
+	if p.tok == token.OPEN || p.tok == token.IS {
+		file_open_information := ast.FileOpenInformation{Node: ast.Node{Pos: p.pos}}
+		if p.tok == token.OPEN {
+			p.next()
+			file_open_kind, error := p.parseExpression()
+			if error != nil {
+				return file_declaration, errors.New("invalid file open kind")
+			}
+			file_open_information.FileOpenKindExpression = file_open_kind
+		}
+		if p.expect(token.IS) == token.NoPos {
+			return file_declaration, errors.New("invalid file open information")
+		}
+		file_logical_name, error := p.parseExpression()
+		if error != nil {
+			return file_declaration, errors.New("invalid file logical name")
+		}
+		file_open_information.FileLogicalName = file_logical_name
+		file_open_information.End = p.prevEnd
+		file_declaration.FileOpenInformation = &file_open_information
+	}
+
+	if p.expect(token.SEMICOLON) == token.NoPos {
+		return file_declaration, errors.New("invalid file declaration")
+	}
+	file_declaration.End = p.prevEnd
+	return file_declaration, nil
+}
+
+func (p *Parser) parseSubtypeDeclaration() (ast.SubtypeDeclaration, error) {
+	subtype_declaration := ast.SubtypeDeclaration{Node: ast.Node{Pos: p.pos}}
+	if p.trace {
+		defer un(trace(p, "SubtypeDeclaration"))
+	}
+	if p.expect(token.SUBTYPE) == token.NoPos {
+		return subtype_declaration, errors.New("invalid subtype declaration")
+	}
+	subtype_declaration.Identifier = p.identifier()
+	if p.expect(token.IDENT) == token.NoPos || p.expect(token.IS) == token.NoPos {
+		return subtype_declaration, errors.New("invalid subtype declaration")
+	}
+
+	subtype_indication, error := p.parseSubtypeIndication()
+	if error != nil {
+		return subtype_declaration, errors.New("invalid subtype indication")
+	}
+	subtype_declaration.SubtypeIndication = subtype_indication
+
+	if p.expect(token.SEMICOLON) == token.NoPos {
+		return subtype_declaration, errors.New("invalid subtype declaration")
+	}
+	subtype_declaration.End = p.prevEnd
+	return subtype_declaration, nil
+}
+
+func (p *Parser) parseComponentDeclaration() (ast.ComponentDeclaration, error) {
+	component_declaration := ast.ComponentDeclaration{Node: ast.Node{Pos: p.pos}}
+	if p.trace {
+		defer un(trace(p, "ComponentDeclaration"))
+	}
+	if p.expect(token.COMPONENT) == token.NoPos {
+		return component_declaration, errors.New("invalid component declaration")
+	}
+	component_declaration.Identifier = p.identifier()
+	if p.expect(token.IDENT) == token.NoPos {
+		return component_declaration, errors.New("invalid component declaration")
+	}
+	if p.tok == token.IS {
+		p.checkStandard(p.pos, token.VHDL93, "IS in a component declaration")
+		p.next()
+	}
+
+	if p.tok == token.GENERIC {
+		generic_clause, error := p.parseFormalGenericClause()
+		if error != nil {
+			return component_declaration, errors.New("invalid generic clause")
+		}
+		component_declaration.LocalGenericClause = &generic_clause
+	}
+	if p.tok == token.PORT {
+		port_clause, error := p.parseFormalPortClause()
+		if error != nil {
+			return component_declaration, errors.New("invalid port clause")
+		}
+		component_declaration.LocalPortClause = &port_clause
+	}
+
+	if p.expect(token.END) == token.NoPos {
+		return component_declaration, errors.New("invalid component declaration")
+	}
+	if p.tok == token.COMPONENT {
+		p.next()
+	} else {
+		p.checkStandard(p.pos, token.VHDL2019, "END without COMPONENT")
+	}
+	if p.tok == token.IDENT {
+		component_declaration.ComponentSimpleName = &ast.SimpleName{Identifier: p.identifier()}
+		if !token.EqualIdent(component_declaration.Identifier.Identifier, p.lit) {
+			p.errorExpected(p.pos, "expected %s, found %s", component_declaration.Identifier.Identifier, p.lit)
+		}
+		p.next()
+	}
+
+	if p.expect(token.SEMICOLON) == token.NoPos {
+		return component_declaration, errors.New("invalid component declaration")
+	}
+	component_declaration.End = p.prevEnd
+	return component_declaration, nil
+}
+
+func (p *Parser) parseAliasDeclaration() (ast.AliasDeclaration, error) {
+	alias_declaration := ast.AliasDeclaration{Node: ast.Node{Pos: p.pos}}
+	if p.trace {
+		defer un(trace(p, "AliasDeclaration"))
+	}
+	if p.expect(token.ALIAS) == token.NoPos {
+		return alias_declaration, errors.New("invalid alias declaration")
+	}
+
+	switch p.tok {
+	case token.IDENT:
+		alias_declaration.AliasDesignator = p.identifier()
+	case token.CHAR:
+		alias_declaration.AliasDesignator = ast.CharacterLiteral{GraphicCharacter: ast.GraphicCharacter{Character: p.lit}}
+	case token.STRING:
+		alias_declaration.AliasDesignator = ast.OperatorSymbol{Symbol: p.lit}
+	default:
+		p.errorExpected(p.pos, "expected alias designator, found %s", p.tok)
+		return alias_declaration, errors.New("invalid alias designator")
+	}
+	p.next()
+
+	if p.tok == token.COLON {
+		p.next()
+		subtype_indication, error := p.parseSubtypeIndication()
+		if error != nil {
+			return alias_declaration, errors.New("invalid subtype indication")
+		}
+		alias_declaration.SubtypeIndication = &subtype_indication
+	}
+
+	if p.expect(token.IS) == token.NoPos {
+		return alias_declaration, errors.New("invalid alias declaration")
+	}
+	name, error := p.parseName()
+	if error != nil {
+		return alias_declaration, errors.New("invalid name")
+	}
+	alias_declaration.Name = name
+
+	if p.tok == token.LSQPAREN {
+		signature, error := p.parseSignature()
+		if error != nil {
+			return alias_declaration, errors.New("invalid signature")
+		}
+		alias_declaration.Signature = &signature
+	}
+
+	if p.expect(token.SEMICOLON) == token.NoPos {
+		return alias_declaration, errors.New("invalid alias declaration")
+	}
+	alias_declaration.End = p.prevEnd
+	return alias_declaration, nil
+}
+
+func (p *Parser) parseSignature() (ast.Signature, error) {
+	signature := ast.Signature{Node: ast.Node{Pos: p.pos}}
+	if p.expect(token.LSQPAREN) == token.NoPos {
+		return signature, errors.New("invalid signature")
+	}
+
+	if p.tok != token.RETURN && p.tok != token.RSQPAREN {
+		for {
+			type_mark, error := p.parseTypeMark()
+			if error != nil {
+				return signature, errors.New("invalid type mark")
+			}
+			signature.TypeMarks = append(signature.TypeMarks, type_mark)
+			if p.tok != token.COMMA {
+				break
+			}
+			p.next()
+		}
+	}
+	if p.tok == token.RETURN {
+		p.next()
+		return_type_mark, error := p.parseTypeMark()
+		if error != nil {
+			return signature, errors.New("invalid type mark")
+		}
+		signature.ReturnTypeMark = return_type_mark
+	}
+
+	if p.expect(token.RSQPAREN) == token.NoPos {
+		return signature, errors.New("invalid signature")
+	}
+	signature.End = p.prevEnd
+	return signature, nil
+}
+
+// parseAttributeDeclarationOrSpecification parses an attribute declaration,
+// attribute identifier : type_mark, or an attribute specification, attribute identifier of ...
+func (p *Parser) parseAttributeDeclarationOrSpecification() (ast.BlockDeclarativeItem, error) {
+	var declarative_item ast.BlockDeclarativeItem
+	if p.trace {
+		defer un(trace(p, "Attribute"))
+	}
+	pos := p.pos
+	if p.expect(token.ATTRIBUTE) == token.NoPos {
+		return declarative_item, errors.New("invalid attribute")
+	}
+	identifier := p.identifier()
+	if p.expect(token.IDENT) == token.NoPos {
+		return declarative_item, errors.New("invalid attribute")
+	}
+
+	if p.tok == token.COLON {
+		attribute_declaration := ast.AttributeDeclaration{Identifier: identifier, Node: ast.Node{Pos: pos}}
+		p.next()
+		type_mark, error := p.parseTypeMark()
+		if error != nil {
+			return attribute_declaration, errors.New("invalid type mark")
+		}
+		attribute_declaration.TypeMark = type_mark
+		if p.expect(token.SEMICOLON) == token.NoPos {
+			return attribute_declaration, errors.New("invalid attribute declaration")
+		}
+		attribute_declaration.End = p.prevEnd
+		return attribute_declaration, nil
+	}
+
+	attribute_specification := ast.AttributeSpecification{AttributeDesignator: identifier, EntityNameKeyword: token.ILLEGAL, Node: ast.Node{Pos: pos}}
+	if p.expect(token.OF) == token.NoPos {
+		return attribute_specification, errors.New("invalid attribute specification")
+	}
+
+	if p.tok == token.OTHERS || p.tok == token.ALL {
+		attribute_specification.EntityNameKeyword = p.tok
+		p.next()
+	} else {
+		for {
+			entity_designator, error := p.parseEntityDesignator()
+			if error != nil {
+				return attribute_specification, errors.New("invalid entity designator")
+			}
+			attribute_specification.EntityNameList = append(attribute_specification.EntityNameList, entity_designator)
+			if p.tok != token.COMMA {
+				break
+			}
+			p.next()
+		}
+	}
+
+	if p.expect(token.COLON) == token.NoPos {
+		return attribute_specification, errors.New("invalid attribute specification")
+	}
+	if !isEntityClass(p.tok) {
+		p.errorExpected(p.pos, "expected entity class, found %s", p.tok)
+		return attribute_specification, errors.New("invalid entity class")
+	}
+	attribute_specification.EntityClass = p.tok
+	p.next()
+
+	if p.expect(token.IS) == token.NoPos {
+		return attribute_specification, errors.New("invalid attribute specification")
+	}
+	expression, error := p.parseExpression()
+	if error != nil {
+		return attribute_specification, errors.New("invalid expression")
+	}
+	attribute_specification.Expression = expression
+
+	if p.expect(token.SEMICOLON) == token.NoPos {
+		return attribute_specification, errors.New("invalid attribute specification")
+	}
+	attribute_specification.End = p.prevEnd
+	return attribute_specification, nil
+}
+
+func (p *Parser) parseEntityDesignator() (ast.EntityDesignator, error) {
+	entity_designator := ast.EntityDesignator{Node: ast.Node{Pos: p.pos}}
+	switch p.tok {
+	case token.IDENT:
+		entity_designator.EntityTag = ast.SimpleName{Identifier: p.identifier()}
+	case token.CHAR:
+		entity_designator.EntityTag = ast.CharacterLiteral{GraphicCharacter: ast.GraphicCharacter{Character: p.lit}}
+	case token.STRING:
+		entity_designator.EntityTag = ast.OperatorSymbol{Symbol: p.lit}
+	default:
+		p.errorExpected(p.pos, "expected entity tag, found %s", p.tok)
+		return entity_designator, errors.New("invalid entity tag")
+	}
+	p.next()
+
+	if p.tok == token.LSQPAREN {
+		signature, error := p.parseSignature()
+		if error != nil {
+			return entity_designator, errors.New("invalid signature")
+		}
+		entity_designator.Signature = &signature
+	}
+	entity_designator.End = p.prevEnd
+	return entity_designator, nil
+}
+
+// isEntityClass reports whether tok is an entity class of an attribute specification.
+func isEntityClass(tok token.Token) bool {
+	switch tok {
+	case token.ENTITY, token.ARCHITECTURE, token.CONFIGURATION, token.PROCEDURE, token.FUNCTION,
+		token.PACKAGE, token.TYPE, token.SUBTYPE, token.CONSTANT, token.SIGNAL, token.VARIABLE,
+		token.COMPONENT, token.LABEL, token.LITERAL, token.UNITS, token.GROUP, token.FILE,
+		token.PROPERTY, token.SEQUENCE, token.VIEW:
+		return true
+	}
+	return false
+}
+
+func (p *Parser) parseConfigurationSpecification() (ast.ConfigurationSpecification, error) {
+	configuration_specification := ast.ConfigurationSpecification{InstantiationKeyword: token.ILLEGAL, Node: ast.Node{Pos: p.pos}}
+	if p.trace {
+		defer un(trace(p, "ConfigurationSpecification"))
+	}
+	if p.expect(token.FOR) == token.NoPos {
+		return configuration_specification, errors.New("invalid configuration specification")
+	}
+
+	if p.tok == token.OTHERS || p.tok == token.ALL {
+		configuration_specification.InstantiationKeyword = p.tok
+		p.next()
+	} else {
+		instantiation_list, error := p.parseIdentifierList()
+		if error != nil {
+			return configuration_specification, errors.New("invalid instantiation list")
+		}
+		configuration_specification.InstantiationList = instantiation_list
+	}
+
+	if p.expect(token.COLON) == token.NoPos {
+		return configuration_specification, errors.New("invalid component specification")
+	}
+	component_name, error := p.parseTypeMark()
+	if error != nil {
+		return configuration_specification, errors.New("invalid component name")
+	}
+	configuration_specification.ComponentName = component_name
+
+	binding_indication, error := p.parseBindingIndication()
+	if error != nil {
+		return configuration_specification, errors.New("invalid binding indication")
+	}
+	configuration_specification.BindingIndication = binding_indication
+
+	if p.expect(token.SEMICOLON) == token.NoPos {
+		return configuration_specification, errors.New("invalid configuration specification")
+	}
+	if p.tok == token.END && p.tok2 == token.FOR {
+		p.checkStandard(p.pos, token.VHDL2008, "END FOR in a configuration specification")
+		p.next()
+		p.next()
+		if p.expect(token.SEMICOLON) == token.NoPos {
+			return configuration_specification, errors.New("invalid configuration specification")
+		}
+	}
+	configuration_specification.End = p.prevEnd
+	return configuration_specification, nil
+}
+
+func (p *Parser) parseBindingIndication() (ast.BindingIndication, error) {
+	binding_indication := ast.BindingIndication{Node: ast.Node{Pos: p.pos}}
+	if p.trace {
+		defer un(trace(p, "BindingIndication"))
+	}
+
+	if p.tok == token.USE {
+		p.next()
+		entity_aspect, error := p.parseEntityAspect()
+		if error != nil {
+			return binding_indication, errors.New("invalid entity aspect")
+		}
+		binding_indication.EntityAspect = entity_aspect
+	}
+	if p.tok == token.GENERIC {
+		generic_map_aspect, error := p.parseGenericMapAspect(false)
+		if error != nil {
+			return binding_indication, errors.New("invalid generic map aspect")
+		}
+		binding_indication.GenericMapAspect = &generic_map_aspect
+	}
+	if p.tok == token.PORT {
+		port_map_aspect, error := p.parsePortMapAspect()
+		if error != nil {
+			return binding_indication, errors.New("invalid port map aspect")
+		}
+		binding_indication.PortMapAspect = &port_map_aspect
+	}
+
+	binding_indication.End = p.prevEnd
+	return binding_indication, nil
+}
+
+func (p *Parser) parseEntityAspect() (ast.EntityAspect, error) {
+	var entity_aspect ast.EntityAspect
+	pos := p.pos
+	switch p.tok {
+	case token.ENTITY:
+		p.next()
+		entity_name, error := p.parseTypeMark()
+		if error != nil {
+			return entity_aspect, errors.New("invalid entity name")
+		}
+		entity_aspect_entity := ast.EntityAspectEntity{EntityName: entity_name, Node: ast.Node{Pos: pos}}
+		if p.tok == token.LPAREN {
+			p.next()
+			architecture_identifier := p.identifier()
+			if p.expect(token.IDENT) == token.NoPos || p.expect(token.RPAREN) == token.NoPos {
+				return entity_aspect_entity, errors.New("invalid architecture identifier")
+			}
+			entity_aspect_entity.ArchitectureIdentifier = &architecture_identifier
+		}
+		entity_aspect_entity.End = p.prevEnd
+		return entity_aspect_entity, nil
+	case token.CONFIGURATION:
+		p.next()
+		configuration_name, error := p.parseTypeMark()
+		if error != nil {
+			return entity_aspect, errors.New("invalid configuration name")
+		}
+		return ast.EntityAspectConfiguration{ConfigurationName: configuration_name, Node: ast.Node{Pos: pos, End: p.prevEnd}}, nil
+	case token.OPEN:
+		open := ast.Keyword{Token: p.tok, Value: p.lit}
+		p.next()
+		return open, nil
+	default:
+		p.errorExpected(p.pos, "expected ENTITY, CONFIGURATION or OPEN, found %s", p.tok)
+	}
+	return entity_aspect, errors.New("invalid entity aspect")
+}
+
+// parseDisconnectionSpecification parses disconnect signal_list : type_mark after
+// time_expression ;
+func (p *Parser) parseDisconnectionSpecification() (ast.DisconnectionSpecification, error) {
+	disconnection_specification := ast.DisconnectionSpecification{SignalKeyword: token.ILLEGAL, Node: ast.Node{Pos: p.pos}}
+	if p.trace {
+		defer un(trace(p, "DisconnectionSpecification"))
+	}
+	if p.expect(token.DISCONNECT) == token.NoPos {
+		return disconnection_specification, errors.New("invalid disconnection specification")
+	}
+
+	if p.tok == token.OTHERS || p.tok == token.ALL {
+		disconnection_specification.SignalKeyword = p.tok
+		p.next()
+	} else {
+		for {
+			signal_name, error := p.parseName()
+			if error != nil {
+				return disconnection_specification, errors.New("invalid signal name")
+			}
+			disconnection_specification.SignalList = append(disconnection_specification.SignalList, signal_name)
+			if p.tok != token.COMMA {
+				break
+			}
+			p.next()
+		}
+	}
+
+	if p.expect(token.COLON) == token.NoPos {
+		return disconnection_specification, errors.New("invalid guarded signal specification")
+	}
+	type_mark, error := p.parseTypeMark()
+	if error != nil {
+		return disconnection_specification, errors.New("invalid type mark")
+	}
+	disconnection_specification.TypeMark = type_mark
+
+	if p.expect(token.AFTER) == token.NoPos {
+		return disconnection_specification, errors.New("invalid disconnection specification")
+	}
+	after, error := p.parseExpression()
+	if error != nil {
+		return disconnection_specification, errors.New("invalid expression")
+	}
+	disconnection_specification.After = after
+
+	if p.expect(token.SEMICOLON) == token.NoPos {
+		return disconnection_specification, errors.New("invalid disconnection specification")
+	}
+	disconnection_specification.End = p.prevEnd
+	return disconnection_specification, nil
+}
+
+// parseGroupTemplateOrDeclaration parses a group template declaration,
+// group identifier is ( entity_class_entry_list ), or a group declaration,
+// group identifier : group_template_name ( group_constituent_list ).
+func (p *Parser) parseGroupTemplateOrDeclaration() (ast.BlockDeclarativeItem, error) {
+	var declarative_item ast.BlockDeclarativeItem
+	if p.trace {
+		defer un(trace(p, "Group"))
+	}
+	pos := p.pos
+	if p.expect(token.GROUP) == token.NoPos {
+		return declarative_item, errors.New("invalid group")
+	}
+	identifier := p.identifier()
+	if p.expect(token.IDENT) == token.NoPos {
+		return declarative_item, errors.New("invalid group")
+	}
+
+	if p.tok == token.IS {
+		group_template := ast.GroupTemplateDeclaration{Identifier: identifier, Node: ast.Node{Pos: pos}}
+		p.next()
+		if p.expect(token.LPAREN) == token.NoPos {
+			return group_template, errors.New("invalid group template declaration")
+		}
+		for {
+			entity_class_entry := ast.EntityClassEntry{EntityClass: p.tok, Node: ast.Node{Pos: p.pos}}
+			if !isEntityClass(p.tok) {
+				p.errorExpected(p.pos, "expected entity class, found %s", p.tok)
+				return group_template, errors.New("invalid entity class")
+			}
+			p.next()
+			if p.tok == token.BOX {
+				entity_class_entry.Box = true
+				p.next()
+			}
+			entity_class_entry.End = p.prevEnd
+			group_template.EntityClassEntries = append(group_template.EntityClassEntries, entity_class_entry)
+			if p.tok != token.COMMA {
+				break
+			}
+			p.next()
+		}
+		if p.expect(token.RPAREN) == token.NoPos || p.expect(token.SEMICOLON) == token.NoPos {
+			return group_template, errors.New("invalid group template declaration")
+		}
+		group_template.End = p.prevEnd
+		return group_template, nil
+	}
+
+	group_declaration := ast.GroupDeclaration{Identifier: identifier, Node: ast.Node{Pos: pos}}
+	if p.expect(token.COLON) == token.NoPos {
+		return group_declaration, errors.New("invalid group declaration")
+	}
+	group_template_name, error := p.parseTypeMark()
+	if error != nil {
+		return group_declaration, errors.New("invalid group template name")
+	}
+	group_declaration.GroupTemplateName = group_template_name
+
+	if p.expect(token.LPAREN) == token.NoPos {
+		return group_declaration, errors.New("invalid group declaration")
+	}
+	for {
+		var group_constituent ast.Name
+		if p.tok == token.CHAR {
+			group_constituent = ast.CharacterLiteral{GraphicCharacter: ast.GraphicCharacter{Character: p.lit}}
+			p.next()
+		} else {
+			name, error := p.parseName()
+			if error != nil {
+				return group_declaration, errors.New("invalid group constituent")
+			}
+			group_constituent = name
+		}
+		group_declaration.GroupConstituents = append(group_declaration.GroupConstituents, group_constituent)
+		if p.tok != token.COMMA {
+			break
+		}
+		p.next()
+	}
+	if p.expect(token.RPAREN) == token.NoPos || p.expect(token.SEMICOLON) == token.NoPos {
+		return group_declaration, errors.New("invalid group declaration")
+	}
+	group_declaration.End = p.prevEnd
+	return group_declaration, nil
+}
+
+// parsePackageInstantiationDeclaration parses the VHDL-2008 package identifier is new
+// uninstantiated_package_name [ generic_map_aspect ] ; nested package declarations and
+// bodies are reported as not supported.
+func (p *Parser) parsePackageInstantiationDeclaration() (ast.PackageInstantiationDeclaration, error) {
+	package_instantiation := ast.PackageInstantiationDeclaration{Node: ast.Node{Pos: p.pos}}
+	if p.trace {
+		defer un(trace(p, "PackageInstantiationDeclaration"))
+	}
+	if p.expect(token.PACKAGE) == token.NoPos {
+		return package_instantiation, errors.New("invalid package instantiation")
+	}
+	if p.tok == token.BODY {
+		p.error(package_instantiation.Pos, "package bodies are not supported as declarative items")
+		return package_instantiation, errors.New("unsupported package body")
+	}
+
+	package_instantiation.Identifier = p.identifier()
+	if p.expect(token.IDENT) == token.NoPos || p.expect(token.IS) == token.NoPos {
+		return package_instantiation, errors.New("invalid package instantiation")
+	}
+	if p.tok != token.NEW {
+		p.error(package_instantiation.Pos, "package declarations are not supported as declarative items")
+		return package_instantiation, errors.New("unsupported package declaration")
+	}
+	p.checkStandard(package_instantiation.Pos, token.VHDL2008, "package instantiation")
+	p.next()
+
+	uninstantiated_package_name, error := p.parseTypeMark()
+	if error != nil {
+		return package_instantiation, errors.New("invalid package name")
+	}
+	package_instantiation.UninstantiatedPackageName = uninstantiated_package_name
+
+	if p.tok == token.GENERIC {
+		generic_map_aspect, error := p.parseGenericMapAspect(false)
+		if error != nil {
+			return package_instantiation, errors.New("invalid generic map aspect")
+		}
+		package_instantiation.GenericMapAspect = &generic_map_aspect
+	}
+
+	if p.expect(token.SEMICOLON) == token.NoPos {
+		return package_instantiation, errors.New("invalid package instantiation")
+	}
+	package_instantiation.End = p.prevEnd
+	return package_instantiation, nil
+}
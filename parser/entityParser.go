@@ -15,12 +15,10 @@ func (p *Parser) parseEntityDeclaration() (ast.EntityDeclaration, error) {
 	if p.expect(token.ENTITY) == token.NoPos {
 		return entity, errors.New("Expected ENTITY keyword")
 	}
-	entity.Identifier.Identifier = p.lit
-	entity.Identifier.Pos = p.pos
+	entity.Identifier = p.identifier()
 	if p.expect(token.IDENT) == token.NoPos {
 		return entity, errors.New("Expected IDENTIFIER")
 	}
-	entity.Identifier.Pos = p.pos
 	if p.expect(token.IS) == token.NoPos {
 		return entity, errors.New("Expected IS keyword")
 	}
@@ -54,13 +52,14 @@ func (p *Parser) parseEntityDeclaration() (ast.EntityDeclaration, error) {
 
 	if p.tok == token.ENTITY {
 		//Consume the entity keyword
+		p.checkStandard(p.pos, token.VHDL93, "END ENTITY")
 		p.next()
 	}
 
 	if p.tok == token.IDENT {
 		//Consume the identifier
-		entity.EntitySimpleName = &ast.SimpleName{Identifier: ast.Identifier{Identifier: p.lit}}
-		if entity.Identifier.Identifier != entity.EntitySimpleName.Identifier.Identifier {
+		entity.EntitySimpleName = &ast.SimpleName{Identifier: p.identifier()}
+		if !token.EqualIdent(entity.Identifier.Identifier, entity.EntitySimpleName.Identifier.Identifier) {
 			p.errorExpected(p.pos, "Expected %s, found %s", entity.Identifier.Identifier, entity.EntitySimpleName.Identifier.Identifier)
 		}
 		p.next()
@@ -79,24 +78,75 @@ func (p *Parser) parseEntityHeader() (ast.EntityHeader, error) {
 	if p.trace {
 		defer un(trace(p, "EntityHeader"))
 	}
-	//TODO add later
-	//If there is a generic clause or port clause consume it
 
 	if p.tok == token.GENERIC {
-		for p.tok != token.SEMICOLON {
-			p.next()
+		generic_clause, error := p.parseFormalGenericClause()
+		if error != nil {
+			return entityHeader, errors.New("Error parsing generic clause")
 		}
+		entityHeader.FormalGenericClause = &generic_clause
 	}
 
 	if p.tok == token.PORT {
-		for p.tok != token.SEMICOLON {
-			p.next()
+		port_clause, error := p.parseFormalPortClause()
+		if error != nil {
+			return entityHeader, errors.New("Error parsing port clause")
 		}
+		entityHeader.FormalPortClause = &port_clause
 	}
 
+	//An empty header ends where it starts
+	entityHeader.End = entityHeader.Pos
+	if entityHeader.FormalGenericClause != nil || entityHeader.FormalPortClause != nil {
+		entityHeader.End = p.prevEnd
+	}
 	return entityHeader, nil
 }
 
+func (p *Parser) parseFormalGenericClause() (ast.FormalGenericClause, error) {
+	generic_clause := ast.FormalGenericClause{Node: ast.Node{Pos: p.pos}}
+	if p.trace {
+		defer un(trace(p, "GenericClause"))
+	}
+	if p.expect(token.GENERIC) == token.NoPos {
+		return generic_clause, errors.New("Expected GENERIC keyword")
+	}
+
+	generic_list, error := p.parseInterfaceList(genericInterface)
+	if error != nil {
+		return generic_clause, errors.New("Error parsing generic list")
+	}
+	generic_clause.GenericList = generic_list
+
+	if p.expect(token.SEMICOLON) == token.NoPos {
+		return generic_clause, errors.New("Expected SEMICOLON")
+	}
+	generic_clause.End = p.prevEnd
+	return generic_clause, nil
+}
+
+func (p *Parser) parseFormalPortClause() (ast.FormalPortClause, error) {
+	port_clause := ast.FormalPortClause{Node: ast.Node{Pos: p.pos}}
+	if p.trace {
+		defer un(trace(p, "PortClause"))
+	}
+	if p.expect(token.PORT) == token.NoPos {
+		return port_clause, errors.New("Expected PORT keyword")
+	}
+
+	port_list, error := p.parseInterfaceList(portInterface)
+	if error != nil {
+		return port_clause, errors.New("Error parsing port list")
+	}
+	port_clause.PortList = port_list
+
+	if p.expect(token.SEMICOLON) == token.NoPos {
+		return port_clause, errors.New("Expected SEMICOLON")
+	}
+	port_clause.End = p.prevEnd
+	return port_clause, nil
+}
+
 func (p *Parser) parseEntityDeclarativePart() (ast.EntityDeclarativePart, error) {
 	//TODO parse entity declarative part
 	//Right now we are going to to skip until the begin or end keyword
@@ -0,0 +1,319 @@
+package parser
+
+import (
+	"errors"
+	"vhdl/ast"
+	"vhdl/token"
+)
+
+// ParseExpr parses the source given to Init as a single expression.
+func (p *Parser) ParseExpr() (ast.Expression, error) {
+	expression, error := p.parseExpression()
+	if error == nil && p.tok != token.EOF {
+		p.errorExpected(p.pos, "expected end of expression, found %s", p.tok)
+	}
+	return expression, p.errors.Err()
+}
+
+// parseExpression parses ?? primary or a logical expression, the binary operators are
+// parsed by precedence.
+func (p *Parser) parseExpression() (ast.Expression, error) {
+	if p.trace {
+		defer un(trace(p, "Expression"))
+	}
+	if p.tok == token.COND_CONV {
+		//The scanner reports ?? before VHDL-2008
+		pos := p.pos
+		p.next()
+		primary, error := p.parsePrimary()
+		if error != nil {
+			return primary, errors.New("invalid condition operator operand")
+		}
+		return ast.UnaryExpr{Op: token.COND_CONV, X: primary, Node: ast.Node{Pos: pos, End: p.prevEnd}}, nil
+	}
+	return p.parseBinaryExpr(token.LowestPrecedence + 1)
+}
+
+// binaryPrecedence returns the precedence of tok as a binary operator, or
+// LowestPrecedence if it is not one. Precedence gives the precedence of + and - as
+// signs, their precedence as adding operators is the alternative one.
+func binaryPrecedence(tok token.Token) int {
+	switch tok {
+	case token.PLUS, token.MINUS:
+		return tok.PrecedenceAlternativeOperator()
+	case token.ABS, token.NOT, token.COND_CONV:
+		return token.LowestPrecedence
+	}
+	return tok.Precedence()
+}
+
+// unaryPrecedence returns the precedence of tok as a unary operator, or LowestPrecedence
+// if it is not one. The logical operators are unary reduction operators since VHDL-2008.
+func unaryPrecedence(tok token.Token) int {
+	switch tok {
+	case token.PLUS, token.MINUS, token.ABS, token.NOT:
+		return tok.Precedence()
+	case token.AND, token.OR, token.NAND, token.NOR, token.XOR, token.XNOR:
+		return tok.PrecedenceAlternativeOperator()
+	}
+	return token.LowestPrecedence
+}
+
+// parseBinaryExpr parses an expression whose binary operators have a precedence of at
+// least prec1.
+func (p *Parser) parseBinaryExpr(prec1 int) (ast.Expression, error) {
+	pos := p.pos
+	x, error := p.parseUnaryExpr(prec1)
+	if error != nil {
+		return x, error
+	}
+
+	for {
+		op, oprec := p.tok, binaryPrecedence(p.tok)
+		if oprec < prec1 {
+			return x, nil
+		}
+		p.checkOperatorSequence(x, op)
+		op_pos := p.pos
+		p.next()
+
+		prec2 := oprec + 1
+		if op == token.EXP {
+			//The exponent is a primary
+			prec2 = token.HighestPrecedence + 1
+		}
+		y, error := p.parseBinaryExpr(prec2)
+		if error != nil {
+			return x, error
+		}
+		x = ast.BinaryExpr{X: x, Op: op, OpPos: op_pos, Y: y, Node: ast.Node{Pos: pos, End: p.prevEnd}}
+	}
+}
+
+// checkOperatorSequence reports an error if op follows the operator of x without
+// parentheses when VHDL does not allow it: different logical operators can not be mixed,
+// nand, nor, the relational, shift and ** operators can not be repeated and ** can not
+// follow abs, not or a unary logical operator.
+func (p *Parser) checkOperatorSequence(x ast.Expression, op token.Token) {
+	if unary, ok := x.(ast.UnaryExpr); ok && op == token.EXP && unary.Op != token.PLUS && unary.Op != token.MINUS {
+		//abs, not and the unary logical operators only take a primary
+		p.error(p.pos, "%s can not follow %s without parentheses", op, unary.Op)
+		return
+	}
+	binary, ok := x.(ast.BinaryExpr)
+	if !ok || binaryPrecedence(binary.Op) != binaryPrecedence(op) {
+		return
+	}
+	prec := binaryPrecedence(op)
+	switch {
+	case op == token.AND || op == token.OR || op == token.XOR || op == token.XNOR:
+		if binary.Op != op {
+			p.error(p.pos, "%s and %s can not be mixed without parentheses", binary.Op, op)
+		}
+	case op == token.NAND || op == token.NOR || op == token.EXP || prec == token.EQL.Precedence() || prec == token.SLL.Precedence():
+		p.error(p.pos, "%s can not follow %s without parentheses", op, binary.Op)
+	}
+}
+
+// parseUnaryExpr parses a unary operator and its operand, or a primary. A sign can only
+// start a simple expression and the other unary operators only apply to a primary.
+func (p *Parser) parseUnaryExpr(prec1 int) (ast.Expression, error) {
+	op := p.tok
+	uprec := unaryPrecedence(op)
+	if uprec == token.LowestPrecedence {
+		return p.parsePrimary()
+	}
+	pos := p.pos
+
+	switch op {
+	case token.PLUS, token.MINUS:
+		if prec1 > binaryPrecedence(op) {
+			p.errorExpected(pos, "expected primary, found %s", op)
+		}
+	case token.ABS, token.NOT:
+		if prec1 > uprec {
+			p.errorExpected(pos, "expected primary, found %s", op)
+		}
+	default:
+		p.checkStandard(pos, token.VHDL2008, "unary "+op.String())
+		if prec1 > uprec {
+			p.errorExpected(pos, "expected primary, found %s", op)
+		}
+	}
+	p.next()
+
+	x, error := p.parseBinaryExpr(uprec + 1)
+	if error != nil {
+		return x, error
+	}
+	return ast.UnaryExpr{Op: op, X: x, Node: ast.Node{Pos: pos, End: p.prevEnd}}, nil
+}
+
+// parsePrimary parses a literal, a name, an aggregate, a parenthesized expression, a
+// qualified expression or an allocator. Function calls and type conversions are parsed as
+// names, they can not be told apart from indexed names without semantic analysis.
+func (p *Parser) parsePrimary() (ast.Expression, error) {
+	var primary ast.Expression
+	if p.trace {
+		defer un(trace(p, "Primary"))
+	}
+	pos := p.pos
+
+	switch p.tok {
+	case token.INT, token.REAL, token.BASED, token.DEC:
+		abstract_literal := ast.BasicLit{Kind: p.tok, Value: p.lit, Node: ast.Node{Pos: pos, End: p.tokenEnd()}}
+		p.next()
+		if p.tok != token.IDENT {
+			return abstract_literal, nil
+		}
+		//An abstract literal followed by a unit name is a physical literal
+		unit_name := ast.SimpleName{Identifier: p.identifier()}
+		p.next()
+		return ast.PhysicalLiteral{AbstractLiteral: abstract_literal, UnitName: unit_name, Node: ast.Node{Pos: pos, End: p.prevEnd}}, nil
+	case token.STRING:
+		if p.tok2 == token.LPAREN {
+			//An operator symbol called as a function, such as "and"(a, b)
+			return p.parseNameOrQualifiedExpression()
+		}
+		fallthrough
+	case token.CHAR, token.BIT_STR, token.NULL:
+		literal := ast.BasicLit{Kind: p.tok, Value: p.lit, Node: ast.Node{Pos: pos, End: p.tokenEnd()}}
+		p.next()
+		return literal, nil
+	case token.IDENT:
+		return p.parseNameOrQualifiedExpression()
+	case token.LPAREN:
+		return p.parseAggregateOrParenExpr()
+	case token.NEW:
+		return p.parseAllocator()
+	default:
+		p.errorExpected(p.pos, "expected expression, found %s", p.tok)
+	}
+	return primary, errors.New("invalid primary")
+}
+
+// parseNameOrQualifiedExpression parses a name, or the type mark and the operand of a
+// qualified expression.
+func (p *Parser) parseNameOrQualifiedExpression() (ast.Expression, error) {
+	pos := p.pos
+	name, error := p.parseName()
+	if error != nil {
+		return name, errors.New("invalid name")
+	}
+	if p.tok != token.APOS {
+		return name, nil
+	}
+	//The name stops before an apostrophe only if a parenthesis follows
+	return p.parseQualifiedExpression(name, pos)
+}
+
+// parseQualifiedExpression parses type_mark ' ( expression ) or type_mark ' aggregate
+// from the apostrophe.
+func (p *Parser) parseQualifiedExpression(type_mark ast.Name, pos token.Pos) (ast.QualifiedExpression, error) {
+	qualified_expression := ast.QualifiedExpression{TypeMark: type_mark, Node: ast.Node{Pos: pos}}
+	if p.expect(token.APOS) == token.NoPos {
+		return qualified_expression, errors.New("invalid qualified expression")
+	}
+	operand, error := p.parseAggregateOrParenExpr()
+	if error != nil {
+		return qualified_expression, errors.New("invalid qualified expression operand")
+	}
+	qualified_expression.Operand = operand
+	qualified_expression.End = p.prevEnd
+	return qualified_expression, nil
+}
+
+// parseAggregateOrParenExpr parses ( expression ) or an aggregate, a single element
+// without choices is a parenthesized expression.
+func (p *Parser) parseAggregateOrParenExpr() (ast.Expression, error) {
+	aggregate := ast.Aggregate{Node: ast.Node{Pos: p.pos}}
+	if p.trace {
+		defer un(trace(p, "Aggregate"))
+	}
+	if p.expect(token.LPAREN) == token.NoPos {
+		return aggregate, errors.New("invalid aggregate")
+	}
+
+	for {
+		element_association, error := p.parseElementAssociation()
+		if error != nil {
+			return aggregate, errors.New("invalid element association")
+		}
+		aggregate.ElementAssociations = append(aggregate.ElementAssociations, element_association)
+		if p.tok != token.COMMA {
+			break
+		}
+		p.next()
+	}
+
+	if p.expect(token.RPAREN) == token.NoPos {
+		return aggregate, errors.New("invalid aggregate")
+	}
+	aggregate.End = p.prevEnd
+	if len(aggregate.ElementAssociations) == 1 && aggregate.ElementAssociations[0].Choices == nil {
+		return ast.ParenExpr{X: aggregate.ElementAssociations[0].Expression, Node: aggregate.Node}, nil
+	}
+	return aggregate, nil
+}
+
+// parseElementAssociation parses [ choices => ] expression.
+func (p *Parser) parseElementAssociation() (ast.ElementAssociation, error) {
+	element_association := ast.ElementAssociation{Node: ast.Node{Pos: p.pos}}
+
+	choices, error := p.parseChoices()
+	if error != nil {
+		return element_association, errors.New("invalid choices")
+	}
+	if p.tok == token.ARROW {
+		p.next()
+		element_association.Choices = choices
+		expression, error := p.parseExpression()
+		if error != nil {
+			return element_association, errors.New("invalid expression")
+		}
+		element_association.Expression = expression
+	} else {
+		//What was parsed is the expression of a positional association
+		if _, others := choices[0].(ast.Keyword); others || len(choices) != 1 {
+			p.errorExpected(p.pos, "expected =>, found %s", p.tok)
+			return element_association, errors.New("invalid element association")
+		}
+		element_association.Expression = choices[0]
+	}
+
+	element_association.End = p.prevEnd
+	return element_association, nil
+}
+
+// parseAllocator parses new subtype_indication or new qualified_expression.
+func (p *Parser) parseAllocator() (ast.Allocator, error) {
+	allocator := ast.Allocator{Node: ast.Node{Pos: p.pos}}
+	if p.trace {
+		defer un(trace(p, "Allocator"))
+	}
+	if p.expect(token.NEW) == token.NoPos {
+		return allocator, errors.New("invalid allocator")
+	}
+
+	//Both alternatives start with a type mark, only a qualified expression has an
+	//apostrophe after it
+	c := p.checkpoint()
+	pos := p.pos
+	if type_mark, error := p.parseTypeMark(); error == nil && p.tok == token.APOS {
+		qualified_expression, error := p.parseQualifiedExpression(type_mark, pos)
+		if error != nil {
+			return allocator, errors.New("invalid qualified expression")
+		}
+		allocator.Operand = qualified_expression
+	} else {
+		p.restore(c)
+		subtype_indication, error := p.parseSubtypeIndication()
+		if error != nil {
+			return allocator, errors.New("invalid subtype indication")
+		}
+		allocator.Operand = subtype_indication
+	}
+
+	allocator.End = p.prevEnd
+	return allocator, nil
+}
@@ -0,0 +1,372 @@
+package parser
+
+import (
+	"errors"
+	"vhdl/ast"
+	"vhdl/token"
+)
+
+// interfaceKind is the kind of interface list being parsed, it decides the object
+// class of the declarations without a class keyword and the declarations allowed.
+type interfaceKind int
+
+const (
+	genericInterface interfaceKind = iota
+	portInterface
+	parameterInterface
+)
+
+// parseInterfaceList parses ( interface_element { ; interface_element } ).
+func (p *Parser) parseInterfaceList(kind interfaceKind) (ast.InterfaceList, error) {
+	interface_list := ast.InterfaceList{Node: ast.Node{Pos: p.pos}}
+	if p.trace {
+		defer un(trace(p, "InterfaceList"))
+	}
+	if p.expect(token.LPAREN) == token.NoPos {
+		return interface_list, errors.New("invalid interface list")
+	}
+
+	for {
+		interface_declaration, error := p.parseInterfaceDeclaration(kind)
+		if error != nil {
+			return interface_list, errors.New("invalid interface declaration")
+		}
+		interface_list.InterfaceElements = append(interface_list.InterfaceElements, interface_declaration)
+		if p.tok != token.SEMICOLON {
+			break
+		}
+		semicolon := p.pos
+		p.next()
+		if p.tok == token.RPAREN {
+			p.checkStandard(semicolon, token.VHDL2019, "final semicolon in an interface list")
+			break
+		}
+	}
+
+	if p.expect(token.RPAREN) == token.NoPos {
+		return interface_list, errors.New("invalid interface list")
+	}
+	interface_list.End = p.prevEnd
+	return interface_list, nil
+}
+
+func (p *Parser) parseInterfaceDeclaration(kind interfaceKind) (ast.InterfaceDeclaration, error) {
+	var interface_declaration ast.InterfaceDeclaration
+	if p.trace {
+		defer un(trace(p, "InterfaceDeclaration"))
+	}
+
+	switch p.tok {
+	case token.TYPE, token.PROCEDURE, token.FUNCTION, token.PURE, token.IMPURE, token.PACKAGE:
+		if kind != genericInterface {
+			p.errorExpected(p.pos, "expected interface object declaration, found %s", p.tok)
+			return interface_declaration, errors.New("invalid interface declaration")
+		}
+	}
+
+	switch p.tok {
+	case token.TYPE:
+		p.checkStandard(p.pos, token.VHDL2008, "generic type")
+		interface_type := ast.InterfaceTypeDeclaration{Node: ast.Node{Pos: p.pos}}
+		p.next()
+		interface_type.Identifier = p.identifier()
+		if p.expect(token.IDENT) == token.NoPos {
+			return interface_type, errors.New("invalid interface type declaration")
+		}
+		interface_type.End = p.prevEnd
+		return interface_type, nil
+	case token.PROCEDURE, token.FUNCTION, token.PURE, token.IMPURE:
+		p.checkStandard(p.pos, token.VHDL2008, "generic subprogram")
+		return p.parseInterfaceSubprogramDeclaration()
+	case token.PACKAGE:
+		p.checkStandard(p.pos, token.VHDL2008, "generic package")
+		return p.parseInterfacePackageDeclaration()
+	case token.CONSTANT, token.SIGNAL, token.VARIABLE, token.FILE, token.IDENT:
+		return p.parseInterfaceObjectDeclaration(kind)
+	default:
+		p.errorExpected(p.pos, "expected interface declaration, found %s", p.tok)
+	}
+	return interface_declaration, errors.New("invalid interface declaration")
+}
+
+func (p *Parser) parseInterfaceObjectDeclaration(kind interfaceKind) (ast.InterfaceDeclaration, error) {
+	var interface_declaration ast.InterfaceDeclaration
+	if p.trace {
+		defer un(trace(p, "InterfaceObjectDeclaration"))
+	}
+	pos := p.pos
+
+	class := token.ILLEGAL
+	if p.tok != token.IDENT {
+		class = p.tok
+		p.next()
+	}
+
+	identifier_list, error := p.parseIdentifierList()
+	if error != nil {
+		return interface_declaration, errors.New("invalid identifier list")
+	}
+
+	if p.expect(token.COLON) == token.NoPos {
+		return interface_declaration, errors.New("invalid interface declaration")
+	}
+
+	var mode *ast.Mode
+	switch p.tok {
+	case token.IN, token.OUT, token.INOUT, token.BUFFER, token.LINKAGE:
+		mode = &ast.Mode{Token: p.tok, Node: ast.Node{Pos: p.pos, End: p.tokenEnd()}}
+		p.next()
+	}
+
+	subtype_indication, error := p.parseSubtypeIndication()
+	if error != nil {
+		return interface_declaration, errors.New("invalid subtype indication")
+	}
+
+	bus := false
+	if p.tok == token.BUS {
+		bus = true
+		p.next()
+	}
+
+	var default_expression ast.Expression
+	if p.tok == token.VAR_ASSIGN {
+		p.next()
+		default_expression, error = p.parseExpression()
+		if error != nil {
+			return interface_declaration, errors.New("invalid default expression")
+		}
+	}
+	node := ast.Node{Pos: pos, End: p.prevEnd}
+
+	if class == token.ILLEGAL {
+		//LRM 6.5.2 the object class is implied by the interface list and the mode
+		switch {
+		case kind == genericInterface:
+			class = token.CONSTANT
+		case kind == portInterface:
+			class = token.SIGNAL
+		case mode == nil || mode.Token == token.IN:
+			class = token.CONSTANT
+		default:
+			class = token.VARIABLE
+		}
+	}
+	if kind == genericInterface && class != token.CONSTANT {
+		p.error(pos, "only constants are allowed in a generic list, found %s", class)
+	}
+	if kind == portInterface && class != token.SIGNAL {
+		p.error(pos, "only signals are allowed in a port list, found %s", class)
+	}
+	if class == token.CONSTANT && mode != nil && mode.Token != token.IN {
+		p.error(mode.Pos, "the mode of an interface constant must be IN, found %s", mode.Token)
+	}
+	if bus && class != token.SIGNAL {
+		p.error(pos, "only signals can be of kind BUS, found %s", class)
+	}
+
+	switch class {
+	case token.CONSTANT:
+		interface_declaration = ast.InterfaceConstantDeclaration{IdentifierList: identifier_list, Mode: mode, SubtypeIndication: subtype_indication, DefaultExpression: default_expression, Node: node}
+	case token.SIGNAL:
+		interface_declaration = ast.InterfaceSignalDeclaration{IdentifierList: identifier_list, Mode: mode, SubtypeIndication: subtype_indication, Bus: bus, DefaultExpression: default_expression, Node: node}
+	case token.VARIABLE:
+		interface_declaration = ast.InterfaceVariableDeclaration{IdentifierList: identifier_list, Mode: mode, SubtypeIndication: subtype_indication, DefaultExpression: default_expression, Node: node}
+	case token.FILE:
+		if mode != nil || default_expression != nil {
+			p.error(pos, "an interface file declaration has no mode and no default expression")
+		}
+		interface_declaration = ast.InterfaceFileDeclaration{IdentifierList: identifier_list, SubtypeIndication: subtype_indication, Node: node}
+	}
+	return interface_declaration, nil
+}
+
+func (p *Parser) parseInterfaceSubprogramDeclaration() (ast.InterfaceSubprogramDeclaration, error) {
+	interface_subprogram := ast.InterfaceSubprogramDeclaration{Node: ast.Node{Pos: p.pos}}
+	if p.trace {
+		defer un(trace(p, "InterfaceSubprogramDeclaration"))
+	}
+
+	subprogram_specification, error := p.parseSubprogramSpecification()
+	if error != nil {
+		return interface_subprogram, errors.New("invalid subprogram specification")
+	}
+	interface_subprogram.SubprogramSpecification = subprogram_specification
+
+	if p.tok == token.IS {
+		p.next()
+		if p.tok == token.BOX {
+			interface_subprogram.Box = true
+			p.next()
+		} else {
+			subprogram_name, error := p.parseName()
+			if error != nil {
+				return interface_subprogram, errors.New("invalid interface subprogram default")
+			}
+			interface_subprogram.Default = subprogram_name
+		}
+	}
+
+	interface_subprogram.End = p.prevEnd
+	return interface_subprogram, nil
+}
+
+func (p *Parser) parseInterfacePackageDeclaration() (ast.InterfacePackageDeclaration, error) {
+	interface_package := ast.InterfacePackageDeclaration{Node: ast.Node{Pos: p.pos}}
+	if p.trace {
+		defer un(trace(p, "InterfacePackageDeclaration"))
+	}
+
+	if p.expect(token.PACKAGE) == token.NoPos {
+		return interface_package, errors.New("invalid interface package declaration")
+	}
+	interface_package.Identifier = p.identifier()
+	if p.expect(token.IDENT) == token.NoPos {
+		return interface_package, errors.New("invalid interface package declaration")
+	}
+	if p.expect(token.IS) == token.NoPos || p.expect(token.NEW) == token.NoPos {
+		return interface_package, errors.New("invalid interface package declaration")
+	}
+
+	uninstantiated_package_name, error := p.parseTypeMark()
+	if error != nil {
+		return interface_package, errors.New("invalid package name")
+	}
+	interface_package.UninstantiatedPackageName = uninstantiated_package_name
+
+	generic_map_aspect, error := p.parseGenericMapAspect(true)
+	if error != nil {
+		return interface_package, errors.New("invalid generic map aspect")
+	}
+	interface_package.GenericMapAspect = generic_map_aspect
+
+	interface_package.End = p.prevEnd
+	return interface_package, nil
+}
+
+// parseGenericMapAspect parses generic map ( association_list ), the generic map of an
+// interface package can also be ( <> ) or ( default ).
+func (p *Parser) parseGenericMapAspect(interface_package bool) (ast.GenericMapAspect, error) {
+	generic_map_aspect := ast.GenericMapAspect{Node: ast.Node{Pos: p.pos}}
+	if p.trace {
+		defer un(trace(p, "GenericMapAspect"))
+	}
+	if p.expect(token.GENERIC) == token.NoPos || p.expect(token.MAP) == token.NoPos {
+		return generic_map_aspect, errors.New("invalid generic map aspect")
+	}
+
+	if interface_package && p.tok == token.LPAREN && (p.tok2 == token.BOX || p.tok2 == token.DEFAULT) {
+		p.next()
+		generic_map_aspect.Box = p.tok == token.BOX
+		generic_map_aspect.Default = p.tok == token.DEFAULT
+		p.next()
+		if p.expect(token.RPAREN) == token.NoPos {
+			return generic_map_aspect, errors.New("invalid generic map aspect")
+		}
+	} else {
+		association_list, error := p.parseAssociationList()
+		if error != nil {
+			return generic_map_aspect, errors.New("invalid association list")
+		}
+		generic_map_aspect.AssociationList = association_list
+	}
+
+	generic_map_aspect.End = p.prevEnd
+	return generic_map_aspect, nil
+}
+
+func (p *Parser) parsePortMapAspect() (ast.PortMapAspect, error) {
+	port_map_aspect := ast.PortMapAspect{Node: ast.Node{Pos: p.pos}}
+	if p.trace {
+		defer un(trace(p, "PortMapAspect"))
+	}
+	if p.expect(token.PORT) == token.NoPos || p.expect(token.MAP) == token.NoPos {
+		return port_map_aspect, errors.New("invalid port map aspect")
+	}
+
+	association_list, error := p.parseAssociationList()
+	if error != nil {
+		return port_map_aspect, errors.New("invalid association list")
+	}
+	port_map_aspect.AssociationList = association_list
+
+	port_map_aspect.End = p.prevEnd
+	return port_map_aspect, nil
+}
+
+// parseAssociationList parses ( association_element { , association_element } ).
+func (p *Parser) parseAssociationList() ([]ast.AssociationElement, error) {
+	var association_list []ast.AssociationElement
+	if p.trace {
+		defer un(trace(p, "AssociationList"))
+	}
+	if p.expect(token.LPAREN) == token.NoPos {
+		return association_list, errors.New("invalid association list")
+	}
+
+	for {
+		association_element, error := p.parseAssociationElement()
+		if error != nil {
+			return association_list, errors.New("invalid association element")
+		}
+		association_list = append(association_list, association_element)
+		if p.tok != token.COMMA {
+			break
+		}
+		p.next()
+	}
+
+	if p.expect(token.RPAREN) == token.NoPos {
+		return association_list, errors.New("invalid association list")
+	}
+	return association_list, nil
+}
+
+func (p *Parser) parseAssociationElement() (ast.AssociationElement, error) {
+	association_element := ast.AssociationElement{Node: ast.Node{Pos: p.pos}}
+
+	actual_part, error := p.parseActualPart()
+	if error != nil {
+		return association_element, errors.New("invalid actual part")
+	}
+	if p.tok == token.ARROW {
+		//What was parsed is the formal part
+		p.next()
+		association_element.Formal = actual_part
+		actual_part, error = p.parseActualPart()
+		if error != nil {
+			return association_element, errors.New("invalid actual part")
+		}
+	}
+	association_element.Actual = actual_part
+
+	association_element.End = p.prevEnd
+	return association_element, nil
+}
+
+// parseActualPart parses an actual, it is an expression, the OPEN keyword or a
+// discrete range in the arguments of a slice name.
+func (p *Parser) parseActualPart() (ast.Expression, error) {
+	if p.tok == token.OPEN {
+		open := ast.Keyword{Token: p.tok, Value: p.lit}
+		p.next()
+		return open, nil
+	}
+	return p.parseRange()
+}
+
+func (p *Parser) parseIdentifierList() (ast.IdentifierList, error) {
+	var identifier_list ast.IdentifierList
+	for {
+		identifier := p.identifier()
+		if p.expect(token.IDENT) == token.NoPos {
+			return identifier_list, errors.New("invalid identifier list")
+		}
+		identifier_list = append(identifier_list, identifier)
+		if p.tok != token.COMMA {
+			break
+		}
+		p.next()
+	}
+	return identifier_list, nil
+}
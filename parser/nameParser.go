@@ -2,45 +2,33 @@ package parser
 
 import (
 	"errors"
-	"runtime/trace"
 	"vhdl/ast"
 	"vhdl/token"
 )
 
 func (p *Parser) parseName() (ast.Name, error) {
-	var name any
+	var name ast.Name
 	if p.trace {
 		defer un(trace(p, "Name"))
 	}
-    switch p.tok {
-    case token.IDENT:
-        p.parseNamePart()
-        //Check if is selected_name, simple_name, indexed_name, slice_name, attribute_name
-    case token.STRING:
-        //Is operator_symbol
-    case token.CHAR:
-        //Is character_literal
-    case default:
-        return name, errors.New("invalid name")
-    }
-
-	return name, nil
-}
+	pos := p.pos
+	switch p.tok {
+	case token.IDENT:
+		name = ast.SimpleName{Identifier: p.identifier()}
+	case token.STRING:
+		//Is operator_symbol
+		name = ast.OperatorSymbol{Symbol: p.lit}
+	case token.CHAR:
+		//Is character_literal
+		name = ast.CharacterLiteral{GraphicCharacter: ast.GraphicCharacter{Character: p.lit}}
+	default:
+		p.errorExpected(p.pos, "expected name, found %s", p.tok)
+		return name, errors.New("invalid name")
+	}
+	p.next()
 
-func (p *Parser) parseNamePart() (ast.NamePart, error) {
-    if p.trace {
-        defer un(trace(p, "NamePart"))
-    }
-    var name any 
-    switch p.tok {
-    case token.DOT:
-    case Token.LPAREN:
-        p.parseFunctionCallOrIndexedNameOrSliceName()
-    case token.APOS:
-        p.parseAttributeName()
-    default:
-        return name, errors.New("Invalid namepart")
-    }
+	//Check if is selected_name, indexed_name, slice_name or attribute_name
+	return p.recursiveParseName(name, pos)
 }
 
 func (p *Parser) parseSimpleName() (ast.SimpleName, error) {
@@ -49,7 +37,7 @@ func (p *Parser) parseSimpleName() (ast.SimpleName, error) {
 		defer un(trace(p, "SimpleName"))
 	}
 
-	identifier := ast.Identifier{Identifier: p.lit}
+	identifier := p.identifier()
 	simple_name = ast.SimpleName{Identifier: identifier}
 
 	if p.expect(token.IDENT) == token.NoPos {
@@ -64,23 +52,18 @@ func (p *Parser) parseSelectedName() (ast.SelectedName, error) {
 	if p.trace {
 		defer un(trace(p, "SelectedName"))
 	}
+	pos := p.pos
 
-	prefix, error := p.parsePrefix()
+	name, error := p.parseName()
 	if error != nil {
-		return selected_name, errors.New("invalid prefix")
+		return selected_name, errors.New("invalid name")
 	}
-	selected_name.Prefix = prefix
-
-	if p.expect(token.DOT) == token.NoPos {
+	selected_name, ok := name.(ast.SelectedName)
+	if !ok {
+		p.errorExpected(pos, "expected selected name")
 		return selected_name, errors.New("invalid selected name")
 	}
 
-	suffix, error := p.parseSuffix()
-	if error != nil {
-		return selected_name, errors.New("invalid suffix")
-	}
-	selected_name.Suffix = suffix
-
 	return selected_name, nil
 }
 
@@ -89,7 +72,7 @@ func (p *Parser) parsePrefix() (ast.Prefix, error) {
 	if p.trace {
 		defer un(trace(p, "Prefix"))
 	}
-	name, error := p.recursiveParseName(nil)
+	name, error := p.parseName()
 	if error != nil {
 		return prefix, errors.New("invalid name")
 	}
@@ -98,25 +81,117 @@ func (p *Parser) parsePrefix() (ast.Prefix, error) {
 	return prefix, nil
 }
 
-func (p *Parser) recursiveParseName(name_option any) (ast.Name, error) {
-	var name any
+// recursiveParseName parses the suffixes that follow the prefix name starting at pos.
+func (p *Parser) recursiveParseName(name ast.Name, pos token.Pos) (ast.Name, error) {
+	for {
+		switch p.tok {
+		case token.DOT:
+			p.next()
+			suffix, error := p.parseSuffix()
+			if error != nil {
+				p.errorExpected(p.pos, "expected suffix, found %s", p.tok)
+				return name, errors.New("invalid suffix")
+			}
+			name = ast.SelectedName{Prefix: name, Suffix: suffix}
+		case token.APOS:
+			if p.tok2 == token.LPAREN {
+				//A qualified expression, not a name
+				return name, nil
+			}
+			p.next()
+			attribute_name, error := p.parseAttributeDesignator(name, pos)
+			if error != nil {
+				return name, error
+			}
+			name = attribute_name
+		case token.LPAREN:
+			arguments, error := p.parseNameArguments(name, pos)
+			if error != nil {
+				return name, error
+			}
+			name = arguments
+		default:
+			return name, nil
+		}
+	}
+}
+
+// parseAttributeDesignator parses the designator after the apostrophe of an attribute
+// name, it can be a reserved word such as range or subtype.
+func (p *Parser) parseAttributeDesignator(prefix ast.Prefix, pos token.Pos) (ast.AttributeName, error) {
+	attribute_name := ast.AttributeName{Prefix: prefix, Designator: p.identifier(), Node: ast.Node{Pos: pos}}
+	if p.tok != token.IDENT && !p.tok.IsKeyword() {
+		p.errorExpected(p.pos, "expected attribute designator, found %s", p.tok)
+		return attribute_name, errors.New("invalid attribute name")
+	}
+	p.next()
+	attribute_name.End = p.prevEnd
+	return attribute_name, nil
+}
+
+// parseNameArguments parses the parenthesized part of an indexed name, a slice name or
+// a function call. They can only be told apart by their content: a single range is a
+// slice and named associations make a function call.
+func (p *Parser) parseNameArguments(prefix ast.Prefix, pos token.Pos) (ast.Name, error) {
 	if p.trace {
-		defer un(trace(p, "Name"))
+		defer un(trace(p, "NameArguments"))
+	}
+	elements, error := p.parseAssociationList()
+	if error != nil {
+		return prefix, errors.New("invalid name")
+	}
+	node := ast.Node{Pos: pos, End: p.prevEnd}
+
+	var expressions []ast.Expression
+	for _, element := range elements {
+		if element.Formal != nil {
+			return ast.FunctionCall{Name: prefix, ParameterAssociations: elements, Node: node}, nil
+		}
+		expressions = append(expressions, element.Actual)
+	}
+	if len(expressions) == 1 {
+		if discrete_range, ok := expressions[0].(ast.SimpleRange); ok {
+			return ast.SliceName{Prefix: prefix, DiscreteRange: discrete_range, Node: node}, nil
+		}
+	}
+	return ast.IndexedName{Prefix: prefix, Expressions: expressions, Node: node}, nil
+}
+
+// parseTypeMark parses a type or subtype name. It is a simple or a selected name,
+// possibly followed by an attribute such as 'subtype, but never by an index: a
+// parenthesis after a type mark starts a constraint.
+func (p *Parser) parseTypeMark() (ast.Name, error) {
+	var type_mark ast.Name
+	if p.trace {
+		defer un(trace(p, "TypeMark"))
+	}
+	pos := p.pos
+
+	simple_name, error := p.parseSimpleName()
+	if error != nil {
+		return type_mark, errors.New("invalid type mark")
 	}
+	type_mark = simple_name
 
-	//Check if is selected_name
-	if p.tok == token.IDENT && p.tok2 == token.DOT {
-		//Call recursivePrefix function
-		var selected_name ast.SelectedName
-		if name_option == nil {
-			name_option = ast.SelectedName{}
-			//name_option.Prefix = p.lit
+	for p.tok == token.DOT {
+		p.next()
+		suffix, error := p.parseSimpleName()
+		if error != nil {
+			return type_mark, errors.New("invalid type mark")
 		}
-		selected_name.Prefix = name_option
+		type_mark = ast.SelectedName{Prefix: type_mark, Suffix: suffix}
+	}
+
+	if p.tok == token.APOS && p.tok2 != token.LPAREN {
 		p.next()
+		attribute_name, error := p.parseAttributeDesignator(type_mark, pos)
+		if error != nil {
+			return type_mark, errors.New("invalid type mark")
+		}
+		type_mark = attribute_name
 	}
-	return name, nil
 
+	return type_mark, nil
 }
 
 func (p *Parser) parseSuffix() (ast.Suffix, error) {
@@ -126,7 +201,7 @@ func (p *Parser) parseSuffix() (ast.Suffix, error) {
 	}
 	switch p.tok {
 	case token.IDENT:
-		suffix = ast.SimpleName{Identifier: ast.Identifier{Identifier: p.lit}}
+		suffix = ast.SimpleName{Identifier: p.identifier()}
 	case token.STRING:
 		operator_string := ast.OperatorSymbol{Symbol: p.lit}
 		suffix = operator_string
@@ -3,7 +3,9 @@ package parser
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"vhdl/ast"
+	"vhdl/literal"
 	"vhdl/scanner"
 	"vhdl/token"
 )
@@ -18,30 +20,69 @@ const (
 	DeclarationErrors                                 // report declaration errors
 	SpuriousErrors                                    // same as AllErrors, for backward-compatibility
 	SkipObjectResolution                              // skip deprecated identifier resolution; see ParseFile
+	SkipPragmaRegions                                 // drop the text between off and on synthesis pragmas
+	KeepContent                                       // keep the source on the file for token.File.Text and CodeFrame
 	AllErrors            = SpuriousErrors             // report all errors (not just the first 10 on different lines)
 )
 
+// The language standard is one of the following values, it decides which words are
+// reserved and which productions are allowed. Without one the parser follows VHDL-2019.
+const (
+	VHDL2019 Mode = Mode(token.VHDL2019) << stdShift
+	VHDL2008 Mode = Mode(token.VHDL2008) << stdShift
+	VHDL2002 Mode = Mode(token.VHDL2002) << stdShift
+	VHDL93   Mode = Mode(token.VHDL93) << stdShift
+	VHDL87   Mode = Mode(token.VHDL87) << stdShift
+
+	stdShift      = 16
+	stdMask  Mode = 0xF << stdShift
+)
+
+// Standard returns the language standard selected by the mode.
+func (m Mode) Standard() token.Standard {
+	return token.Standard(m & stdMask >> stdShift)
+}
+
+var scannerStandards = [...]scanner.Mode{
+	token.VHDL2019: scanner.VHDL2019,
+	token.VHDL2008: scanner.VHDL2008,
+	token.VHDL2002: scanner.VHDL2002,
+	token.VHDL93:   scanner.VHDL93,
+	token.VHDL87:   scanner.VHDL87,
+}
+
 // The parser structure holds the parser's internal state.
 type Parser struct {
 	file    *token.File
 	errors  scanner.ErrorList
 	scanner scanner.Scanner
 	mode    Mode
+	std     token.Standard // language standard
 	trace   bool
 	indent  int // indentation used for tracing output
 
 	// Next token
 	pos token.Pos   // token position
+	end token.Pos   // position immediately after the token
 	tok token.Token // one token look-ahead
 	lit string      // token literal
 
+	prevEnd token.Pos // position immediately after the previous token
+
 	//Lookahead + 2 token
 	pos2 token.Pos
+	end2 token.Pos
 	tok2 token.Token
 	lit2 string
 
-	exprLev int  // < 0: in control clause, >= 0: in expression
-	inRhs   bool // if set, the parser is parsing a rhs expression
+	// Synthesis pragmas
+	pragmas   []ast.Pragma
+	regions   []ast.PragmaRegion
+	regionOff token.Pos // position of the pending off pragma, or NoPos
+
+	// Protected envelopes
+	protected []ast.ProtectedEnvelope
+	envelope  *ast.ProtectedEnvelope // envelope being read, or nil
 
 	// nestLev is used to track and limit the recursion depth
 	// during parsing.
@@ -50,8 +91,13 @@ type Parser struct {
 
 func (p *Parser) Init(fset *token.FileSet, filename string, src []byte, mode Mode) {
 	p.file = fset.AddFile(filename, -1, len(src))
+	p.std = mode.Standard()
 	error_handler := func(pos token.Position, msg string) { p.errors.Add(pos, msg) }
-	p.scanner.Init(p.file, src, error_handler, scanner.ScanComments)
+	scanner_mode := scanner.ScanComments | scannerStandards[p.std]
+	if mode&KeepContent != 0 {
+		scanner_mode |= scanner.KeepContent
+	}
+	p.scanner.Init(p.file, src, error_handler, scanner_mode)
 
 	p.mode = mode
 	p.trace = mode&Trace != 0 // for convenience (p.trace is used frequently)
@@ -126,21 +172,99 @@ func (p *Parser) next0() {
 		}
 	}
 
+	p.prevEnd = p.tokenEnd()
 	for {
 		//Move the lookahead token to the next token
-		p.pos, p.tok, p.lit = p.pos2, p.tok2, p.lit2
+		p.pos, p.end, p.tok, p.lit = p.pos2, p.end2, p.tok2, p.lit2
 		//Get the lookahead + 2 token
 		p.pos2, p.tok2, p.lit2 = p.scanner.Scan()
+		p.end2 = p.scanner.End()
+		if p.tok == token.PRAGMA {
+			p.pragma()
+			continue
+		}
+		if p.tok == token.PROTECT || p.tok == token.PROTECT_DATA {
+			p.protect()
+			continue
+		}
+		if p.envelope != nil && p.tok != token.EOF {
+			//The content of a protected envelope is a black box
+			continue
+		}
 		if p.tok == token.COMMENT {
 			if p.mode&ParseComments == 0 {
 				continue
 			}
 		}
+		if p.regionOff.IsValid() && p.mode&SkipPragmaRegions != 0 && p.tok != token.EOF {
+			continue
+		}
 		break
 
 	}
 }
 
+// pragma records the PRAGMA token and the region it opens or closes.
+func (p *Parser) pragma() {
+	pragma, _ := literal.ParsePragma(p.lit)
+	p.pragmas = append(p.pragmas, ast.Pragma{Pragma: pragma, Node: ast.Node{Pos: p.pos, End: p.end}})
+	switch pragma.Region {
+	case literal.RegionOff:
+		//Nested off pragmas do not start a new region
+		if !p.regionOff.IsValid() {
+			p.regionOff = p.pos
+		}
+	case literal.RegionOn:
+		if p.regionOff.IsValid() {
+			p.regions = append(p.regions, ast.PragmaRegion{Off: p.regionOff, On: p.pos})
+			p.regionOff = token.NoPos
+		}
+	}
+}
+
+// protect records the tool directives and the encoded blocks of a protected envelope.
+// Directives outside of begin_protected and end_protected are for the encryption tool.
+func (p *Parser) protect() {
+	end := p.end
+	if p.tok == token.PROTECT_DATA {
+		if p.envelope != nil {
+			keyword := ""
+			if n := len(p.envelope.Directives); n > 0 {
+				keyword = p.envelope.Directives[n-1].Keyword
+			}
+			block := ast.ProtectBlock{Keyword: keyword, Data: p.lit, Node: ast.Node{Pos: p.pos, End: end}}
+			p.envelope.Blocks = append(p.envelope.Blocks, block)
+		}
+		return
+	}
+
+	keyword := ""
+	if words := strings.Fields(strings.ReplaceAll(p.lit, "=", " = ")); len(words) > 1 {
+		keyword = strings.ToLower(words[1])
+	}
+	if keyword == "begin_protected" {
+		if p.envelope != nil {
+			p.error(p.pos, "begin_protected directive inside a protected envelope")
+			p.endEnvelope(p.pos)
+		}
+		p.envelope = &ast.ProtectedEnvelope{Node: ast.Node{Pos: p.pos}}
+	}
+	if p.envelope == nil {
+		return
+	}
+	directive := ast.ProtectDirective{Keyword: keyword, Text: p.lit, Node: ast.Node{Pos: p.pos, End: end}}
+	p.envelope.Directives = append(p.envelope.Directives, directive)
+	if keyword == "end_protected" {
+		p.endEnvelope(end)
+	}
+}
+
+func (p *Parser) endEnvelope(end token.Pos) {
+	p.envelope.End = end
+	p.protected = append(p.protected, *p.envelope)
+	p.envelope = nil
+}
+
 // Peeks into the next next token.
 func (p *Parser) peek() token.Token {
 	return p.tok
@@ -176,17 +300,37 @@ func (p *Parser) next() {
 
 }
 
+// ParseFile parses the design units of the source. The errors found are returned as a
+// scanner.ErrorList sorted by position, or nil if there are none.
 func (p *Parser) ParseFile() (ast.File, error) {
 	file := ast.File{}
 
 	var designUnits []ast.DesignUnit
 	for p.tok != token.EOF {
+		pos := p.pos
 		if designUnit, error := p.ParseDesignUnit(); error == nil {
 			designUnits = append(designUnits, designUnit)
 		}
+		if p.pos == pos {
+			//Nothing was consumed, skip the token to make progress
+			p.next()
+		}
 	}
 	file.DesignUnits = designUnits
-	return file, nil
+	if p.regionOff.IsValid() {
+		p.error(p.regionOff, "synthesis off pragma without a matching on pragma")
+		p.regions = append(p.regions, ast.PragmaRegion{Off: p.regionOff})
+		p.regionOff = token.NoPos
+	}
+	if p.envelope != nil {
+		p.error(p.envelope.Pos, "protected envelope not terminated by an end_protected directive")
+		p.endEnvelope(p.pos)
+	}
+	file.Protected = p.protected
+	file.Pragmas = p.pragmas
+	file.PragmaRegions = p.regions
+	p.errors.Sort()
+	return file, p.errors.Err()
 }
 
 func (p *Parser) ParseDesignUnit() (ast.DesignUnit, error) {
@@ -292,14 +436,14 @@ func (p *Parser) parseLibraryClause() (ast.LibraryClause, error) {
 	if p.expect(token.LIBRARY) == token.NoPos {
 		return lib_clause, errors.New("invalid library clause")
 	}
-	lib_clause.LogicalNameList.LogicalName = ast.LogicalName{Identifier: ast.Identifier{Identifier: p.lit}}
+	lib_clause.LogicalNameList.LogicalName = ast.LogicalName{Identifier: p.identifier()}
 	if p.expect(token.IDENT) == token.NoPos {
 		return lib_clause, errors.New("invalid library clause")
 	}
 	var logical_names []ast.LogicalName
 	for p.tok == token.COMMA {
 		p.next()
-		logical_name := ast.LogicalName{Identifier: ast.Identifier{Identifier: p.lit}}
+		logical_name := ast.LogicalName{Identifier: p.identifier()}
 		if p.expect(token.IDENT) == token.NoPos {
 			return lib_clause, errors.New("invalid library clause")
 		}
@@ -405,6 +549,33 @@ func (p *Parser) parseSecondaryUnit() (ast.SecondaryUnit, error) {
 	return ast.ArchitectureBody{}, nil
 }
 
+// identifier returns the current token as an identifier without consuming it.
+func (p *Parser) identifier() ast.Identifier {
+	return ast.Identifier{Identifier: p.lit, Extended: token.IsExtendedIdent(p.lit), Node: ast.Node{Pos: p.pos, End: p.tokenEnd()}}
+}
+
+// tokenEnd returns the position immediately after the current token. It comes from the
+// scanner, the literal of a Latin-1 source is longer than its text.
+func (p *Parser) tokenEnd() token.Pos {
+	if !p.pos.IsValid() {
+		return token.NoPos
+	}
+	return p.end
+}
+
+// skipTo skips tokens up to one of the stop tokens or EOF, it resynchronizes the
+// parser after a production that could not be parsed.
+func (p *Parser) skipTo(stop ...token.Token) {
+	for p.tok != token.EOF {
+		for _, tok := range stop {
+			if p.tok == tok {
+				return
+			}
+		}
+		p.next()
+	}
+}
+
 func (p *Parser) expect(tok token.Token) token.Pos {
 	pos := token.NoPos
 	if p.tok != tok {
@@ -416,14 +587,85 @@ func (p *Parser) expect(tok token.Token) token.Pos {
 	return pos
 }
 
-func (p *Parser) revert(pos token.Pos) {
-	p.pos = pos
-	p.scanner.RevertPos(pos)
-	p.next()
+// checkpoint is the state of the parser before an ambiguous production, such as a
+// name, a function call or a type conversion. restore returns to it to try another
+// alternative without duplicating the errors and the pragmas found in between.
+type checkpoint struct {
+	scanner   scanner.Checkpoint
+	pos, pos2 token.Pos
+	end, end2 token.Pos
+	prevEnd   token.Pos
+	tok, tok2 token.Token
+	lit, lit2 string
+	errors    int
+	pragmas   int
+	regions   int
+	regionOff token.Pos
+	protected int
+	envelope  *ast.ProtectedEnvelope
+}
+
+func (p *Parser) checkpoint() checkpoint {
+	c := checkpoint{
+		scanner:   p.scanner.Checkpoint(),
+		pos:       p.pos,
+		pos2:      p.pos2,
+		end:       p.end,
+		end2:      p.end2,
+		prevEnd:   p.prevEnd,
+		tok:       p.tok,
+		tok2:      p.tok2,
+		lit:       p.lit,
+		lit2:      p.lit2,
+		errors:    len(p.errors),
+		pragmas:   len(p.pragmas),
+		regions:   len(p.regions),
+		regionOff: p.regionOff,
+		protected: len(p.protected),
+	}
+	if p.envelope != nil {
+		//The envelope being read keeps growing, its slices only grow
+		envelope := *p.envelope
+		c.envelope = &envelope
+	}
+	return c
+}
+
+func (p *Parser) restore(c checkpoint) {
+	p.scanner.Restore(c.scanner)
+	p.pos, p.tok, p.lit = c.pos, c.tok, c.lit
+	p.pos2, p.tok2, p.lit2 = c.pos2, c.tok2, c.lit2
+	p.end, p.end2 = c.end, c.end2
+	p.prevEnd = c.prevEnd
+	p.errors = p.errors[:c.errors]
+	p.pragmas = p.pragmas[:c.pragmas]
+	p.regions = p.regions[:c.regions]
+	p.regionOff = c.regionOff
+	p.protected = p.protected[:c.protected]
+	p.envelope = nil
+	if c.envelope != nil {
+		envelope := *c.envelope
+		p.envelope = &envelope
+	}
+}
+
+// checkStandard reports an error if the construct at pos, introduced by the since
+// revision of the standard, is not part of the selected standard.
+func (p *Parser) checkStandard(pos token.Pos, since token.Standard, construct string) {
+	if !p.std.Includes(since) {
+		p.error(pos, "%s requires %s or later (current standard is %s)", construct, since, p.std)
+	}
 }
 
 func (p *Parser) errorExpected(pos token.Pos, format string, args ...interface{}) {
-	p.error(pos, format, args...)
+	msg := fmt.Sprintf(format, args...)
+	if p.tok == token.IDENT {
+		//A reserved word of a newer standard is just an identifier for the selected one
+		if tok := token.Lookup(p.lit); tok != token.IDENT && !p.std.Includes(tok.Since()) {
+			msg += fmt.Sprintf(" (%s is a reserved word since %s, current standard is %s)", p.lit, tok.Since(), p.std)
+		}
+	}
+	p.error(pos, "%s", msg)
 }
 
 func (p *Parser) error(pos token.Pos, format string, args ...interface{}) {
@@ -0,0 +1,1106 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"vhdl/ast"
+	"vhdl/scanner"
+	"vhdl/token"
+)
+
+func parseSource(src string, mode Mode) (ast.File, scanner.ErrorList) {
+	var p Parser
+	p.Init(token.NewFileSet(), "test.vhd", []byte(src), mode)
+	file, _ := p.ParseFile()
+	return file, p.errors
+}
+
+func TestKeepContent(t *testing.T) {
+	src := "entity e is\nend;\n"
+	for _, mode := range []Mode{0, KeepContent} {
+		var p Parser
+		p.Init(token.NewFileSet(), "test.vhd", []byte(src), mode)
+		p.ParseFile()
+		got, want := p.file.LineText(2), ""
+		if mode&KeepContent != 0 {
+			want = "end;"
+		}
+		if got != want {
+			t.Errorf("mode %d: got line %q, want %q", mode, got, want)
+		}
+	}
+}
+
+func TestParseFileErrors(t *testing.T) {
+	src := `
+architecture rtl of e is
+begin
+    process (all) begin end process;
+    a <= ;
+end;
+`
+	var p Parser
+	p.Init(token.NewFileSet(), "test.vhd", []byte(src), VHDL93)
+	_, err := p.ParseFile()
+	list, ok := err.(scanner.ErrorList)
+	if !ok || len(list) != 2 {
+		t.Fatalf("got %v, want two errors", err)
+	}
+	if !strings.Contains(list[0].Msg, "requires VHDL-2008") || list[0].Pos.Line > list[1].Pos.Line {
+		t.Errorf("got %v, want the standard error first", list)
+	}
+}
+
+func TestEndLabelCaseInsensitive(t *testing.T) {
+	src := `
+entity Uart is
+end entity UART;
+
+architecture Rtl of uart is
+begin
+end RTL;
+`
+	file, errs := parseSource(src, 0)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(file.DesignUnits) != 2 {
+		t.Fatalf("got %d design units, want 2", len(file.DesignUnits))
+	}
+	entity, ok := file.DesignUnits[0].LibraryUnit.(ast.EntityDeclaration)
+	if !ok {
+		t.Fatalf("got %T, want ast.EntityDeclaration", file.DesignUnits[0].LibraryUnit)
+	}
+	if entity.Identifier.Identifier != "Uart" || entity.Identifier.Normalized() != "uart" {
+		t.Errorf("got identifier %q (%q), want \"Uart\" (\"uart\")", entity.Identifier.Identifier, entity.Identifier.Normalized())
+	}
+}
+
+func TestNodeEnd(t *testing.T) {
+	//The Latin-1 letter is one byte of the source but two of the literal
+	file, errs := parseSource("entity caf\xe9_ent is end;", 0)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	entity := file.DesignUnits[0].LibraryUnit.(ast.EntityDeclaration)
+	if entity.Identifier.Pos != 8 || entity.Identifier.End != 16 {
+		t.Errorf("got identifier at %d-%d, want 8-16", entity.Identifier.Pos, entity.Identifier.End)
+	}
+	if header := entity.EntityHeader; header.Pos != 20 || header.End != header.Pos {
+		t.Errorf("got empty header at %d-%d, want 20-20", header.Pos, header.End)
+	}
+}
+
+func TestEndLabelMismatch(t *testing.T) {
+	_, errs := parseSource("entity uart is end entity uart_top;", 0)
+	if len(errs) == 0 {
+		t.Fatal("expected an error for a mismatched end label")
+	}
+}
+
+func TestExtendedIdentifier(t *testing.T) {
+	file, errs := parseSource(`entity \top level\ is end entity \top level\;`, 0)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	entity := file.DesignUnits[0].LibraryUnit.(ast.EntityDeclaration)
+	if !entity.Identifier.Extended || entity.Identifier.Identifier != `\top level\` {
+		t.Errorf("got %+v, want extended identifier \\top level\\", entity.Identifier)
+	}
+
+	_, errs = parseSource(`entity \Top\ is end entity \top\;`, 0)
+	if len(errs) == 0 {
+		t.Error("extended identifiers are case sensitive, expected an end label error")
+	}
+}
+
+func TestStandardSelection(t *testing.T) {
+	src := "entity view is end entity view;"
+	if _, errs := parseSource(src, VHDL2008); len(errs) != 0 {
+		t.Errorf("VHDL-2008: unexpected errors: %v", errs)
+	}
+	if _, errs := parseSource(src, VHDL2019); len(errs) == 0 {
+		t.Error("VHDL-2019: expected an error, view is a reserved word")
+	}
+
+	_, errs := parseSource("context work.ctx; entity e is end;", VHDL93)
+	if len(errs) == 0 || !strings.Contains(errs[0].Msg, "reserved word since VHDL-2008") {
+		t.Errorf("VHDL-93: got errors %v, want a reserved word error", errs)
+	}
+
+	if _, errs := parseSource("entity e is end entity e;", VHDL87); len(errs) != 1 {
+		t.Errorf("VHDL-87: got errors %v, want END ENTITY error", errs)
+	}
+}
+
+func TestPragmaRegions(t *testing.T) {
+	src := `
+entity A is
+end entity;
+-- synthesis translate_off
+entity Model is
+end entity;
+-- synthesis translate_on
+-- synopsys full_case
+entity B is
+end entity;
+`
+	file, errs := parseSource(src, 0)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(file.Pragmas) != 3 || len(file.PragmaRegions) != 1 {
+		t.Fatalf("got pragmas %v, regions %v", file.Pragmas, file.PragmaRegions)
+	}
+	if region := file.PragmaRegions[0]; region.Off != file.Pragmas[0].Pos || region.On != file.Pragmas[1].Pos {
+		t.Errorf("got region %v, want %v-%v", region, file.Pragmas[0].Pos, file.Pragmas[1].Pos)
+	}
+	if len(file.DesignUnits) != 3 {
+		t.Errorf("got %d design units, want 3", len(file.DesignUnits))
+	}
+
+	file, errs = parseSource(src, SkipPragmaRegions)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	var names []string
+	for _, unit := range file.DesignUnits {
+		names = append(names, unit.LibraryUnit.(ast.EntityDeclaration).Identifier.Identifier)
+	}
+	if got := strings.Join(names, " "); got != "A B" {
+		t.Errorf("got design units %s, want A B", got)
+	}
+
+	_, errs = parseSource("-- pragma synthesis_off\nentity A is end;", 0)
+	if len(errs) != 1 {
+		t.Errorf("got errors %v, want an unmatched off pragma", errs)
+	}
+}
+
+func TestProtectedEnvelope(t *testing.T) {
+	src := "entity A is\nend entity;\n" +
+		"`protect begin_protected\n" +
+		"`protect key_keyowner = \"Vendor\", key_method = \"rsa\"\n" +
+		"`protect key_block\n" +
+		"aGVsbG8gd29ybGQ=\n" +
+		"`protect data_method = \"aes128-cbc\"\n" +
+		"`protect data_block\n" +
+		"U2VjcmV0IElQ'\"#+/\n" +
+		"`protect end_protected\n" +
+		"entity B is\nend entity;\n"
+	file, errs := parseSource(src, 0)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(file.DesignUnits) != 2 {
+		t.Errorf("got %d design units, want 2", len(file.DesignUnits))
+	}
+	if len(file.Protected) != 1 {
+		t.Fatalf("got %d protected envelopes, want 1", len(file.Protected))
+	}
+	envelope := file.Protected[0]
+	var keywords []string
+	for _, directive := range envelope.Directives {
+		keywords = append(keywords, directive.Keyword)
+	}
+	if got := strings.Join(keywords, " "); got != "begin_protected key_keyowner key_block data_method data_block end_protected" {
+		t.Errorf("got directives %s", got)
+	}
+	if len(envelope.Blocks) != 2 || envelope.Blocks[0].Keyword != "key_block" || envelope.Blocks[1].Data != "U2VjcmV0IElQ'\"#+/" {
+		t.Errorf("got blocks %v", envelope.Blocks)
+	}
+
+	_, errs = parseSource("`protect begin_protected\n`protect data_block\nAAAA\n", 0)
+	if len(errs) != 1 {
+		t.Errorf("got errors %v, want an unterminated envelope", errs)
+	}
+}
+
+func TestProtectEndLatin1(t *testing.T) {
+	//The source is not UTF-8, the scanner reads it as Latin-1 and returns UTF-8 literals
+	src := "`protect begin_protected\n`protect author = \"\xe9\xe9\xe9\"\n`protect end_protected\n"
+	file, _ := parseSource(src, 0)
+	if len(file.Protected) != 1 || len(file.Protected[0].Directives) != 3 {
+		t.Fatalf("got envelopes %v, want one with three directives", file.Protected)
+	}
+	directives := file.Protected[0].Directives
+	if end := directives[1].End; end != directives[2].Pos-1 {
+		t.Errorf("got end %d, want %d before the next directive", end, directives[2].Pos-1)
+	}
+}
+
+func TestCheckpoint(t *testing.T) {
+	var p Parser
+	p.Init(token.NewFileSet(), "test.vhd", []byte("-- synopsys full_case\nentity E is 8#9#\n-- pragma translate_off\nend;"), 0)
+	c := p.checkpoint()
+	pos, tok := p.pos, p.tok
+	for p.tok != token.EOF {
+		p.next()
+	}
+	if len(p.errors) != 1 || len(p.pragmas) != 2 || !p.regionOff.IsValid() {
+		t.Fatalf("got errors %v and pragmas %v", p.errors, p.pragmas)
+	}
+	p.restore(c)
+	if p.pos != pos || p.tok != tok || len(p.errors) != 0 || len(p.pragmas) != 1 || p.regionOff.IsValid() {
+		t.Errorf("restored %s at %d with errors %v and pragmas %v", p.tok, p.pos, p.errors, p.pragmas)
+	}
+	p.next()
+	p.next()
+	if p.tok2 != token.BASED || len(p.errors) != 1 {
+		t.Errorf("got %s and errors %v, want BASED and the invalid digit error again", p.tok2, p.errors)
+	}
+}
+
+func TestEntityHeader(t *testing.T) {
+	src := `
+entity uart is
+    generic (
+        BAUD, CLOCK_FREQUENCY : positive;
+        WIDTH                 : natural := 8
+    );
+    port (
+        clock    : in  std_logic;
+        data_in  : in  std_logic_vector(7 downto 0);
+        data_out : out ieee.numeric_std.unsigned(WIDTH - 1 downto 0) := (others => '0');
+        signal irq : buffer resolved std_ulogic bus
+    );
+end uart;
+`
+	file, errs := parseSource(src, 0)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	header := file.DesignUnits[0].LibraryUnit.(ast.EntityDeclaration).EntityHeader
+	if header.FormalGenericClause == nil || header.FormalPortClause == nil {
+		t.Fatalf("got header %+v, want a generic and a port clause", header)
+	}
+
+	generics := header.FormalGenericClause.GenericList.InterfaceElements
+	if len(generics) != 2 {
+		t.Fatalf("got %d generic declarations, want 2", len(generics))
+	}
+	baud := generics[0].(ast.InterfaceConstantDeclaration)
+	if len(baud.IdentifierList) != 2 || baud.IdentifierList[1].Identifier != "CLOCK_FREQUENCY" || baud.DefaultExpression != nil {
+		t.Errorf("got %+v, want BAUD, CLOCK_FREQUENCY without default", baud)
+	}
+	if width := generics[1].(ast.InterfaceConstantDeclaration); width.DefaultExpression == nil {
+		t.Errorf("got %+v, want a default expression", width)
+	}
+
+	ports := header.FormalPortClause.PortList.InterfaceElements
+	if len(ports) != 4 {
+		t.Fatalf("got %d port declarations, want 4", len(ports))
+	}
+	clock := ports[0].(ast.InterfaceSignalDeclaration)
+	if clock.Mode == nil || clock.Mode.Token != token.IN || clock.SubtypeIndication.Constraint != nil {
+		t.Errorf("got %+v, want an unconstrained IN port", clock)
+	}
+	constraint, ok := ports[1].(ast.InterfaceSignalDeclaration).SubtypeIndication.Constraint.(ast.IndexConstraint)
+	if !ok || len(constraint.DiscreteRanges) != 1 || constraint.DiscreteRanges[0].(ast.SimpleRange).Direction != token.DOWNTO {
+		t.Errorf("got constraint %+v, want (7 downto 0)", constraint)
+	}
+	data_out := ports[2].(ast.InterfaceSignalDeclaration)
+	if _, ok := data_out.SubtypeIndication.TypeMark.(ast.SelectedName); !ok || data_out.DefaultExpression == nil {
+		t.Errorf("got %+v, want a selected type mark and a default expression", data_out)
+	}
+	irq := ports[3].(ast.InterfaceSignalDeclaration)
+	if irq.Mode.Token != token.BUFFER || !irq.Bus || irq.SubtypeIndication.ResolutionIndication == nil {
+		t.Errorf("got %+v, want a resolved BUFFER port of kind BUS", irq)
+	}
+}
+
+func TestGenericInterfaces(t *testing.T) {
+	src := `
+entity fifo is
+    generic (
+        type element_t;
+        function "<"(l, r : element_t) return boolean is <>;
+        impure function next_value return element_t is work.gen.next_value;
+        procedure report_error(constant msg : in string; variable count : inout natural);
+        package math is new work.math_pkg generic map (<>)
+    );
+end entity fifo;
+`
+	file, errs := parseSource(src, VHDL2008)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	generics := file.DesignUnits[0].LibraryUnit.(ast.EntityDeclaration).EntityHeader.FormalGenericClause.GenericList.InterfaceElements
+	if len(generics) != 5 {
+		t.Fatalf("got %d generic declarations, want 5", len(generics))
+	}
+	if generic_type := generics[0].(ast.InterfaceTypeDeclaration); generic_type.Identifier.Identifier != "element_t" {
+		t.Errorf("got %+v, want type element_t", generic_type)
+	}
+	less := generics[1].(ast.InterfaceSubprogramDeclaration)
+	if function, ok := less.SubprogramSpecification.(ast.FunctionSpecification); !ok || !less.Box || function.Designator != (ast.OperatorSymbol{Symbol: `"<"`}) {
+		t.Errorf("got %+v, want function \"<\" is <>", less)
+	}
+	if next_value := generics[2].(ast.InterfaceSubprogramDeclaration); next_value.Default == nil || next_value.SubprogramSpecification.(ast.FunctionSpecification).Purity != token.IMPURE {
+		t.Errorf("got %+v, want an impure function with a default", next_value)
+	}
+	parameters := generics[3].(ast.InterfaceSubprogramDeclaration).SubprogramSpecification.(ast.ProcedureSpecification).FormalParameterList.InterfaceElements
+	if _, ok := parameters[1].(ast.InterfaceVariableDeclaration); len(parameters) != 2 || !ok {
+		t.Errorf("got parameters %+v, want a constant and a variable", parameters)
+	}
+	if math := generics[4].(ast.InterfacePackageDeclaration); !math.GenericMapAspect.Box {
+		t.Errorf("got %+v, want generic map (<>)", math)
+	}
+
+	_, errs = parseSource(src, VHDL93)
+	if len(errs) != 5 || !strings.Contains(errs[0].Msg, "generic type requires VHDL-2008") {
+		t.Errorf("VHDL-93: got errors %v, want 5 standard errors", errs)
+	}
+}
+
+func TestInterfaceListErrors(t *testing.T) {
+	_, errs := parseSource("entity e is port (variable v : in bit); end;", 0)
+	if len(errs) != 1 || !strings.Contains(errs[0].Msg, "only signals are allowed in a port list") {
+		t.Errorf("got errors %v, want a port class error", errs)
+	}
+
+	src := "entity e is port (a : in bit; b : out bit;); end;"
+	if _, errs := parseSource(src, 0); len(errs) != 0 {
+		t.Errorf("VHDL-2019: unexpected errors: %v", errs)
+	}
+	if _, errs := parseSource(src, VHDL2008); len(errs) != 1 {
+		t.Errorf("VHDL-2008: got errors %v, want a final semicolon error", errs)
+	}
+}
+
+func TestArchitectureDeclarations(t *testing.T) {
+	src := `
+architecture rtl of uart is
+    constant C_DIV : integer := CLOCK_FREQUENCY / BAUD;
+    signal counter : unsigned(C_WIDTH - 1 downto 0) := (others => '0');
+    signal a, b : std_logic register;
+    type states is (IDLE, SEND, '0');
+    type word is range 0 to 2**16 - 1;
+    type time_t is range 0 to 1000 units fs; ps = 1000 fs; end units time_t;
+    type mem_t is array (natural range <>) of std_logic_vector(7 downto 0);
+    type regs_t is array (0 to 3) of word;
+    type pair is record first, second : integer; end record pair;
+    type node;
+    type node_ptr is access node;
+    type text_file is file of string;
+    subtype byte is std_logic_vector(7 downto 0);
+    shared variable count : counter_t;
+    file input : text open read_mode is "input.txt";
+    alias msb : std_logic is counter(counter'high);
+    alias "and" is ieee.std_logic_1164."and" [std_logic, std_logic return std_logic];
+    attribute keep : boolean;
+    attribute keep of a, b : signal is true;
+    component fifo is
+        generic (DEPTH : natural := 16);
+        port (clk : in std_logic; q : out byte);
+    end component fifo;
+    for all : fifo use entity work.fifo(rtl) generic map (DEPTH => 32);
+    function parity(v : std_logic_vector) return std_logic is
+        variable p : std_logic := '0';
+    begin
+        for i in v'range loop
+            p := p xor v(i);
+        end loop;
+        return p;
+    end function parity;
+    procedure reset(signal s : out std_logic);
+begin
+end architecture rtl;
+`
+	file, errs := parseSource(src, 0)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	architecture := file.DesignUnits[0].LibraryUnit.(ast.ArchitectureBody)
+	items := *architecture.ArchitectureDeclarativePart.BlockDeclarativeItems
+	var kinds []string
+	for _, item := range items {
+		kinds = append(kinds, fmt.Sprintf("%T", item))
+	}
+	want := "ast.ConstantDeclaration ast.SignalDeclaration ast.SignalDeclaration " +
+		"ast.FullTypeDeclaration ast.FullTypeDeclaration ast.FullTypeDeclaration ast.FullTypeDeclaration ast.FullTypeDeclaration ast.FullTypeDeclaration " +
+		"ast.IncompleteTypeDeclaration ast.FullTypeDeclaration ast.FullTypeDeclaration ast.SubtypeDeclaration " +
+		"ast.VariableDeclaration ast.FileDeclaration ast.AliasDeclaration ast.AliasDeclaration " +
+		"ast.AttributeDeclaration ast.AttributeSpecification ast.ComponentDeclaration ast.ConfigurationSpecification " +
+		"ast.SubprogramBody ast.SubprogramDeclaration"
+	if got := strings.Join(kinds, " "); got != want {
+		t.Fatalf("got declarations\n%s\nwant\n%s", got, want)
+	}
+
+	if signal := items[2].(ast.SignalDeclaration); len(signal.IdentifierList) != 2 || signal.SignalKind != token.REGISTER {
+		t.Errorf("got %+v, want two register signals", signal)
+	}
+	if states := items[3].(ast.FullTypeDeclaration).TypeDefinition.(ast.EnumerationTypeDefinition); len(states.EnumerationLiterals) != 3 {
+		t.Errorf("got %+v, want 3 enumeration literals", states)
+	}
+	if _, ok := items[4].(ast.FullTypeDeclaration).TypeDefinition.(ast.RangeTypeDefinition); !ok {
+		t.Errorf("got %T, want a range type definition", items[4].(ast.FullTypeDeclaration).TypeDefinition)
+	}
+	if physical := items[5].(ast.FullTypeDeclaration).TypeDefinition.(ast.PhysicalTypeDefinition); physical.PrimaryUnitDeclaration.Identifier != "fs" || len(physical.SecondaryUnitDeclarations) != 1 {
+		t.Errorf("got %+v, want units fs and ps", physical)
+	}
+	if mem := items[6].(ast.FullTypeDeclaration).TypeDefinition.(ast.UnboundedArrayDefinition); len(mem.IndexSubtypeDefinitions) != 1 {
+		t.Errorf("got %+v, want one index subtype definition", mem)
+	}
+	if regs := items[7].(ast.FullTypeDeclaration).TypeDefinition.(ast.ConstrainedArrayDefinition); len(regs.IndexConstraint.DiscreteRanges) != 1 {
+		t.Errorf("got %+v, want a (0 to 3) index constraint", regs)
+	}
+	if pair := items[8].(ast.FullTypeDeclaration).TypeDefinition.(ast.RecordTypeDefinition); len(pair.ElementDeclarations) != 1 || pair.RecordTypeSimpleName == nil {
+		t.Errorf("got %+v, want one element declaration and a closing name", pair)
+	}
+	if variable := items[13].(ast.VariableDeclaration); !variable.Shared {
+		t.Errorf("got %+v, want a shared variable", variable)
+	}
+	if file := items[14].(ast.FileDeclaration); file.FileOpenInformation == nil || file.FileOpenInformation.FileOpenKindExpression == nil {
+		t.Errorf("got %+v, want file open information", file)
+	}
+	if alias := items[16].(ast.AliasDeclaration); alias.Signature == nil || len(alias.Signature.TypeMarks) != 2 || alias.Signature.ReturnTypeMark == nil {
+		t.Errorf("got %+v, want a signature", alias)
+	}
+	if keep := items[18].(ast.AttributeSpecification); len(keep.EntityNameList) != 2 || keep.EntityClass != token.SIGNAL {
+		t.Errorf("got %+v, want keep of a, b : signal", keep)
+	}
+	if fifo := items[19].(ast.ComponentDeclaration); fifo.LocalGenericClause == nil || len(fifo.LocalPortClause.PortList.InterfaceElements) != 2 {
+		t.Errorf("got %+v, want a generic and two ports", fifo)
+	}
+	binding := items[20].(ast.ConfigurationSpecification).BindingIndication
+	if entity, ok := binding.EntityAspect.(ast.EntityAspectEntity); !ok || entity.ArchitectureIdentifier.Identifier != "rtl" || binding.GenericMapAspect == nil {
+		t.Errorf("got %+v, want entity work.fifo(rtl) with a generic map", binding)
+	}
+	if parity := items[21].(ast.SubprogramBody); len(parity.SubprogramDeclarativeItems) != 1 || parity.Designator == nil {
+		t.Errorf("got %+v, want a variable declaration and a closing designator", parity)
+	}
+	if items[1].(ast.SignalDeclaration).Pos != architecture.Pos+token.Pos(strings.Index(src, "signal counter")-strings.Index(src, "architecture")) {
+		t.Errorf("got signal declaration at %d", items[1].(ast.SignalDeclaration).Pos)
+	}
+}
+
+func TestDeclarationErrors(t *testing.T) {
+	src := `
+architecture rtl of e is
+    signal a : bit
+    signal b : bit;
+    wait;
+    constant c : integer := 1;
+begin
+end;
+`
+	file, errs := parseSource(src, 0)
+	if len(errs) != 2 {
+		t.Fatalf("got errors %v, want a missing semicolon and an unexpected wait", errs)
+	}
+	architecture := file.DesignUnits[0].LibraryUnit.(ast.ArchitectureBody)
+	if items := *architecture.ArchitectureDeclarativePart.BlockDeclarativeItems; len(items) != 1 {
+		t.Errorf("got %d declarations, want the constant declaration after the errors", len(items))
+	}
+}
+
+func TestGroupsAndInstantiations(t *testing.T) {
+	src := `
+architecture rtl of e is
+    group pin2pin is (signal, signal);
+    group path_t is (signal <>);
+    group clocks : path_t (clk, rst, '0');
+    disconnect a, b : std_logic after 5 ns;
+    disconnect others : bit after 0 ns;
+    package fifo_pkg is new work.generic_fifo generic map (WIDTH => 8);
+    function max is new work.generic_max [integer return integer] generic map (T => integer);
+    procedure swap is new swap_generic;
+begin
+end;
+`
+	file, errs := parseSource(src, 0)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	architecture := file.DesignUnits[0].LibraryUnit.(ast.ArchitectureBody)
+	items := *architecture.ArchitectureDeclarativePart.BlockDeclarativeItems
+	var kinds []string
+	for _, item := range items {
+		kinds = append(kinds, fmt.Sprintf("%T", item))
+	}
+	want := "ast.GroupTemplateDeclaration ast.GroupTemplateDeclaration ast.GroupDeclaration " +
+		"ast.DisconnectionSpecification ast.DisconnectionSpecification ast.PackageInstantiationDeclaration " +
+		"ast.SubprogramInstantiationDeclaration ast.SubprogramInstantiationDeclaration"
+	if got := strings.Join(kinds, " "); got != want {
+		t.Fatalf("got declarations\n%s\nwant\n%s", got, want)
+	}
+	if path := items[1].(ast.GroupTemplateDeclaration); len(path.EntityClassEntries) != 1 || !path.EntityClassEntries[0].Box {
+		t.Errorf("got %+v, want signal <>", path)
+	}
+	if clocks := items[2].(ast.GroupDeclaration); len(clocks.GroupConstituents) != 3 {
+		t.Errorf("got %+v, want three constituents", clocks)
+	}
+	if others := items[4].(ast.DisconnectionSpecification); others.SignalKeyword != token.OTHERS || others.After == nil {
+		t.Errorf("got %+v, want disconnect others", others)
+	}
+	if max := items[6].(ast.SubprogramInstantiationDeclaration); max.Kind != token.FUNCTION || max.Signature == nil || max.GenericMapAspect == nil {
+		t.Errorf("got %+v, want a function with a signature and a generic map", max)
+	}
+
+	src = `
+architecture rtl of e is
+    package fifo_pkg is new work.generic_fifo;
+    function max is new generic_max;
+begin
+end;
+`
+	_, errs = parseSource(src, VHDL93)
+	if len(errs) != 2 || !strings.Contains(errs[0].Msg, "package instantiation requires VHDL-2008") || !strings.Contains(errs[1].Msg, "subprogram instantiation requires VHDL-2008") {
+		t.Errorf("got errors %v, want instantiations to require VHDL-2008", errs)
+	}
+
+	src = `
+architecture rtl of e is
+    package body p is
+    end package body p;
+begin
+end;
+`
+	_, errs = parseSource(src, 0)
+	if len(errs) == 0 || errs[0].Msg != "package bodies are not supported as declarative items" {
+		t.Errorf("got errors %v, want package bodies not to be supported", errs)
+	}
+}
+
+func TestUART(t *testing.T) {
+	src, err := os.ReadFile("../test/UART.vhd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	//The file starts with lines that are not VHDL
+	text := string(src)
+	file, errs := parseSource(text[strings.Index(text, "LIBRARY"):], 0)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	architecture := file.DesignUnits[len(file.DesignUnits)-1].LibraryUnit.(ast.ArchitectureBody)
+	assignments, processes := 0, 0
+	for _, statement := range *architecture.ArchitectureStatementPart.ConcurrentStatements {
+		switch statement := statement.(type) {
+		case ast.ConcurrentSimpleSignalAssignment:
+			assignments++
+		case ast.ProcessStatement:
+			processes++
+			if statement.Label == nil || statement.ProcessLabel == nil || len(statement.SensitivityList) != 1 || len(statement.SequentialStatements) == 0 {
+				t.Errorf("got %+v, want a labeled process sensitive to CLOCK", statement)
+			}
+		default:
+			t.Errorf("unexpected statement %T", statement)
+		}
+	}
+	if assignments != 4 || processes != 7 {
+		t.Errorf("got %d assignments and %d processes, want 4 and 7", assignments, processes)
+	}
+}
+
+func TestConcurrentStatements(t *testing.T) {
+	src := `
+architecture rtl of e is
+begin
+    q <= d after 1 ns, '0' after 2 ns;
+    y <= a when s = '0' else b when s = '1' else 'X';
+    lbl : postponed z <= guarded reject 1 ns inertial a;
+    with sel select? o <= a when "0-", b when "10" | "11", unaffected when others;
+    check : assert a = b report "mismatch" severity error;
+    log(a, b);
+    u1 : fifo generic map (16) port map (clk => clk, q => open);
+    u2 : entity work.fifo(rtl) port map (clk, q);
+    u3 : component fifo port map (clk, q);
+    u4 : configuration work.cfg;
+    reg : process (all) is
+        variable v : integer;
+    begin
+        if rising_edge(clk) then
+            q <= d;
+        end if;
+    end process reg;
+    blk : block (en = '1') is
+        port (p : in bit);
+        port map (p => a);
+        signal s : bit;
+    begin
+        s <= guarded p;
+    end block blk;
+    gen : for i in 0 to 3 generate
+        signal t : bit;
+    begin
+        t <= a(i);
+    end generate gen;
+    opt : if first : WIDTH > 8 generate
+        wide <= '1';
+    end first;
+    elsif WIDTH > 4 generate
+        wide <= 'X';
+    else generate
+        wide <= '0';
+    end generate opt;
+    sel_gen : case MODE generate
+        when fast : 0 | 1 => f <= '1';
+        when others => f <= '0';
+    end generate sel_gen;
+end architecture rtl;
+`
+	file, errs := parseSource(src, 0)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	architecture := file.DesignUnits[0].LibraryUnit.(ast.ArchitectureBody)
+	statements := *architecture.ArchitectureStatementPart.ConcurrentStatements
+	var kinds []string
+	for _, statement := range statements {
+		kinds = append(kinds, fmt.Sprintf("%T", statement))
+	}
+	want := "ast.ConcurrentSimpleSignalAssignment ast.ConcurrentConditionalSignalAssignment ast.ConcurrentSimpleSignalAssignment " +
+		"ast.ConcurrentSelectedSignalAssignment ast.ConcurrentAssertionStatement ast.ConcurrentProcedureCallStatement " +
+		"ast.ComponentInstantiationStatement ast.ComponentInstantiationStatement ast.ComponentInstantiationStatement ast.ComponentInstantiationStatement " +
+		"ast.ProcessStatement ast.BlockStatement ast.ForGenerateStatement ast.IfGenerateStatement ast.CaseGenerateStatement"
+	if got := strings.Join(kinds, " "); got != want {
+		t.Fatalf("got statements\n%s\nwant\n%s", got, want)
+	}
+
+	if simple := statements[0].(ast.ConcurrentSimpleSignalAssignment); len(simple.Waveform.WaveformElements) != 2 || simple.Waveform.WaveformElements[1].After == nil {
+		t.Errorf("got %+v, want two waveform elements", simple)
+	}
+	if conditional := statements[1].(ast.ConcurrentConditionalSignalAssignment); len(conditional.ConditionalWaveforms) != 3 || conditional.ConditionalWaveforms[2].Condition != nil {
+		t.Errorf("got %+v, want three waveforms and a final else", conditional)
+	}
+	if guarded := statements[2].(ast.ConcurrentSimpleSignalAssignment); guarded.Label.Identifier != "lbl" || !guarded.Postponed || !guarded.Guarded || guarded.DelayMechanism == nil || guarded.DelayMechanism.RejectTime == nil {
+		t.Errorf("got %+v, want a labeled postponed guarded assignment with a reject time", guarded)
+	}
+	selected := statements[3].(ast.ConcurrentSelectedSignalAssignment)
+	if !selected.Matching || len(selected.SelectedWaveforms) != 3 || len(selected.SelectedWaveforms[1].Choices) != 2 || !selected.SelectedWaveforms[2].Waveform.Unaffected {
+		t.Errorf("got %+v, want a matching select with three waveforms", selected)
+	}
+	if assertion := statements[4].(ast.ConcurrentAssertionStatement); assertion.Assertion.Report == nil || assertion.Assertion.Severity == nil {
+		t.Errorf("got %+v, want report and severity", assertion)
+	}
+	if call := statements[5].(ast.ConcurrentProcedureCallStatement); call.Label != nil {
+		t.Errorf("got %+v, want an unlabeled procedure call", call)
+	}
+	if u1 := statements[6].(ast.ComponentInstantiationStatement); u1.GenericMapAspect == nil || len(u1.PortMapAspect.AssociationList) != 2 {
+		t.Errorf("got %+v, want a generic map and two port associations", u1)
+	}
+	if _, ok := statements[7].(ast.ComponentInstantiationStatement).InstantiatedUnit.(ast.EntityAspectEntity); !ok {
+		t.Errorf("got %T, want an entity", statements[7].(ast.ComponentInstantiationStatement).InstantiatedUnit)
+	}
+	if _, ok := statements[8].(ast.ComponentInstantiationStatement).InstantiatedUnit.(ast.InstantiatedComponent); !ok {
+		t.Errorf("got %T, want a component", statements[8].(ast.ComponentInstantiationStatement).InstantiatedUnit)
+	}
+	if process := statements[10].(ast.ProcessStatement); !process.All || len(process.ProcessDeclarativeItems) != 1 || process.ProcessLabel == nil {
+		t.Errorf("got %+v, want process (all) with a variable", process)
+	}
+	if block := statements[11].(ast.BlockStatement); block.GuardCondition == nil || block.BlockHeader.PortMapAspect == nil || len(block.BlockDeclarativeItems) != 1 || len(block.ConcurrentStatements) != 1 {
+		t.Errorf("got %+v, want a guarded block with a header, a signal and a statement", block)
+	}
+	if generate := statements[12].(ast.ForGenerateStatement); len(generate.GenerateStatementBody.BlockDeclarativeItems) != 1 || len(generate.GenerateStatementBody.ConcurrentStatements) != 1 {
+		t.Errorf("got %+v, want a signal and a statement", generate)
+	}
+	if generate := statements[13].(ast.IfGenerateStatement); len(generate.Alternatives) != 3 || generate.Alternatives[0].GenerateStatementBody.AlternativeLabel == nil || generate.Alternatives[2].Condition != nil {
+		t.Errorf("got %+v, want labeled if, elsif and else alternatives", generate)
+	}
+	if generate := statements[14].(ast.CaseGenerateStatement); len(generate.Alternatives) != 2 || generate.Alternatives[0].AlternativeLabel == nil || len(generate.Alternatives[0].Choices) != 2 {
+		t.Errorf("got %+v, want two alternatives", generate)
+	}
+
+	start := strings.Index(src, "architecture")
+	if process := statements[10].(ast.ProcessStatement); process.Pos != architecture.Pos+token.Pos(strings.Index(src, "reg :")-start) ||
+		process.End != architecture.Pos+token.Pos(strings.Index(src, "end process reg;")+len("end process reg;")-start) {
+		t.Errorf("got process at %d-%d", process.Pos, process.End)
+	}
+
+	_, errs = parseSource(src, VHDL93)
+	if len(errs) != 8 {
+		t.Errorf("got %d errors for VHDL-93, want 8: %v", len(errs), errs)
+	}
+}
+
+func TestAggregateTargets(t *testing.T) {
+	src := `
+architecture rtl of e is
+begin
+    (a, b) <= c;
+    (carry, sum) <= '1' & x when en = '1' else "00";
+    with sel select (hi, lo) <= x when '0', y when others;
+end;
+`
+	file, errs := parseSource(src, 0)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	architecture := file.DesignUnits[0].LibraryUnit.(ast.ArchitectureBody)
+	statements := *architecture.ArchitectureStatementPart.ConcurrentStatements
+	targets := []ast.Name{
+		statements[0].(ast.ConcurrentSimpleSignalAssignment).Target,
+		statements[1].(ast.ConcurrentConditionalSignalAssignment).Target,
+		statements[2].(ast.ConcurrentSelectedSignalAssignment).Target,
+	}
+	for i, target := range targets {
+		if aggregate, ok := target.(ast.Aggregate); !ok || len(aggregate.ElementAssociations) != 2 {
+			t.Errorf("statement %d: got target %+v, want an aggregate of two signals", i, target)
+		}
+	}
+}
+
+func TestConcurrentStatementErrors(t *testing.T) {
+	src := `
+architecture rtl of e is
+begin
+    block begin end block;
+    u1 : postponed fifo port map (clk);
+    p : process begin end process q;
+    process begin end process p;
+    a <= ;
+    b <= c;
+end;
+`
+	file, errs := parseSource(src, 0)
+	want := []string{
+		"expected label before BLOCK",
+		"component instantiation can not be postponed",
+		"expected p, found q",
+		"closing label p of a statement without label",
+		"expected expression, found ;",
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("got errors %v, want %d", errs, len(want))
+	}
+	for i, err := range errs {
+		if !strings.Contains(err.Msg, want[i]) {
+			t.Errorf("error %d: got %q, want %q", i, err.Msg, want[i])
+		}
+	}
+	architecture := file.DesignUnits[0].LibraryUnit.(ast.ArchitectureBody)
+	if statements := *architecture.ArchitectureStatementPart.ConcurrentStatements; len(statements) != 5 {
+		t.Errorf("got %d statements, want all of them but the assignment without value", len(statements))
+	}
+}
+
+func TestSequentialStatements(t *testing.T) {
+	src := `
+architecture rtl of e is
+begin
+    main : process
+        variable v : integer;
+    begin
+        wait on a, b until c = '1' for 10 ns;
+        check : assert v > 0 report "negative" severity warning;
+        report "done";
+        if a = '1' then
+            v := 1;
+        elsif b = '1' then
+            v := 2;
+        else
+            null;
+        end if;
+        decode : case? sel is
+            when "1-" => q <= '1';
+            when "00" | "01" => q <= '0' after 1 ns;
+            when others => null;
+        end case? decode;
+        outer : for i in 0 to 7 loop
+            while v < 10 loop
+                v := v + 1;
+                next outer when v = 5;
+            end loop;
+            exit;
+        end loop outer;
+        loop
+            exit when done;
+        end loop;
+        s <= force in '1';
+        s <= force 1 when c else 0;
+        with sel select s <= force out a when "00", b when others;
+        s <= release;
+        q <= transport a when en = '1' else 'Z';
+        v := 1 when en = '1' else 0;
+        with sel select q <= a when "00", b when others;
+        with sel select? v := 1 when "1-", 0 when others;
+        log(v);
+        return;
+    end process main;
+end;
+`
+	file, errs := parseSource(src, 0)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	architecture := file.DesignUnits[0].LibraryUnit.(ast.ArchitectureBody)
+	process := (*architecture.ArchitectureStatementPart.ConcurrentStatements)[0].(ast.ProcessStatement)
+	statements := process.SequentialStatements
+	var kinds []string
+	for _, statement := range statements {
+		kinds = append(kinds, fmt.Sprintf("%T", statement))
+	}
+	want := "ast.WaitStatement ast.AssertionStatement ast.ReportStatement ast.IfStatement ast.CaseStatement ast.LoopStatement ast.LoopStatement " +
+		"ast.SimpleForceAssignment ast.ConditionalForceAssignment ast.SelectedForceAssignment ast.SimpleReleaseAssignment ast.ConditionalSignalAssignment ast.ConditionalVariableAssignment " +
+		"ast.SelectedSignalAssignment ast.SelectedVariableAssignment ast.ProcedureCallStatement ast.ReturnStatement"
+	if got := strings.Join(kinds, " "); got != want {
+		t.Fatalf("got statements\n%s\nwant\n%s", got, want)
+	}
+
+	if wait := statements[0].(ast.WaitStatement); len(wait.SensitivityClause) != 2 || wait.ConditionClause == nil || wait.TimeoutClause == nil {
+		t.Errorf("got %+v, want on, until and for clauses", wait)
+	}
+	if assertion := statements[1].(ast.AssertionStatement); assertion.Label == nil || assertion.Assertion.Severity == nil {
+		t.Errorf("got %+v, want a labeled assertion with a severity", assertion)
+	}
+	if_statement := statements[3].(ast.IfStatement)
+	if len(if_statement.Alternatives) != 3 || if_statement.Alternatives[2].Condition != nil {
+		t.Fatalf("got %+v, want if, elsif and else", if_statement)
+	}
+	if _, ok := if_statement.Alternatives[2].SequentialStatements[0].(ast.NullStatement); !ok {
+		t.Errorf("got %T, want a null statement", if_statement.Alternatives[2].SequentialStatements[0])
+	}
+	if case_statement := statements[4].(ast.CaseStatement); !case_statement.Matching || len(case_statement.Alternatives) != 3 || len(case_statement.Alternatives[1].Choices) != 2 || case_statement.CaseLabel == nil {
+		t.Errorf("got %+v, want a matching case with three alternatives", case_statement)
+	}
+	outer := statements[5].(ast.LoopStatement)
+	if _, ok := outer.IterationScheme.(ast.ParameterSpecification); !ok || outer.LoopLabel == nil || len(outer.SequentialStatements) != 2 {
+		t.Fatalf("got %+v, want a labeled for loop", outer)
+	}
+	inner := outer.SequentialStatements[0].(ast.LoopStatement)
+	if _, ok := inner.IterationScheme.(ast.WhileScheme); !ok {
+		t.Errorf("got %T, want a while loop", inner.IterationScheme)
+	}
+	if next := inner.SequentialStatements[1].(ast.NextStatement); next.LoopLabel.Identifier.Identifier != "outer" || next.Condition == nil {
+		t.Errorf("got %+v, want next outer when", next)
+	}
+	if plain := statements[6].(ast.LoopStatement); plain.IterationScheme != nil || plain.SequentialStatements[0].(ast.ExitStatement).Condition == nil {
+		t.Errorf("got %+v, want a plain loop with exit when", plain)
+	}
+	if force := statements[7].(ast.SimpleForceAssignment); force.ForceMode != token.IN || force.Expression == nil {
+		t.Errorf("got %+v, want force in", force)
+	}
+	if force := statements[8].(ast.ConditionalForceAssignment); len(force.ConditionalExpressions) != 2 || force.ConditionalExpressions[0].Condition == nil {
+		t.Errorf("got %+v, want a conditional force with an else expression", force)
+	}
+	if force := statements[9].(ast.SelectedForceAssignment); force.ForceMode != token.OUT || len(force.SelectedExpressions) != 2 {
+		t.Errorf("got %+v, want a selected force out with two alternatives", force)
+	}
+	if conditional := statements[11].(ast.ConditionalSignalAssignment); !conditional.DelayMechanism.Transport || len(conditional.ConditionalWaveforms) != 2 {
+		t.Errorf("got %+v, want a transport conditional assignment", conditional)
+	}
+	if conditional := statements[12].(ast.ConditionalVariableAssignment); len(conditional.ConditionalExpressions) != 2 || conditional.ConditionalExpressions[1].Condition != nil {
+		t.Errorf("got %+v, want two conditional expressions", conditional)
+	}
+	if selected := statements[14].(ast.SelectedVariableAssignment); !selected.Matching || len(selected.SelectedExpressions) != 2 {
+		t.Errorf("got %+v, want a matching selected variable assignment", selected)
+	}
+
+	start := strings.Index(src, "architecture")
+	if case_statement := statements[4].(ast.CaseStatement); case_statement.Pos != architecture.Pos+token.Pos(strings.Index(src, "decode :")-start) ||
+		case_statement.End != architecture.Pos+token.Pos(strings.Index(src, "end case? decode;")+len("end case? decode;")-start) {
+		t.Errorf("got case statement at %d-%d", case_statement.Pos, case_statement.End)
+	}
+
+	_, errs = parseSource(src, VHDL93)
+	if len(errs) == 0 {
+		t.Errorf("got no errors for VHDL-93")
+	}
+}
+
+func TestSequentialAggregateTargets(t *testing.T) {
+	src := `
+architecture rtl of e is
+begin
+    process
+    begin
+        (v, w) := t;
+        (a, b) <= x;
+        with sel select (hi, lo) <= x when '0', y when others;
+        (a, b) + c;
+    end process;
+end;
+`
+	file, errs := parseSource(src, 0)
+	if len(errs) != 1 || errs[0].Msg != "expected <= or :=, found +" {
+		t.Fatalf("got errors %v, want the expression statement to be rejected", errs)
+	}
+	architecture := file.DesignUnits[0].LibraryUnit.(ast.ArchitectureBody)
+	statements := (*architecture.ArchitectureStatementPart.ConcurrentStatements)[0].(ast.ProcessStatement).SequentialStatements
+	if len(statements) != 3 {
+		t.Fatalf("got %d statements, want the three assignments", len(statements))
+	}
+	targets := []ast.Name{
+		statements[0].(ast.VariableAssignment).Target,
+		statements[1].(ast.SimpleSignalAssignment).Target,
+		statements[2].(ast.SelectedSignalAssignment).Target,
+	}
+	for i, target := range targets {
+		if _, ok := target.(ast.Aggregate); !ok {
+			t.Errorf("statement %d: got target %T, want an aggregate", i, target)
+		}
+	}
+}
+
+func TestSequentialStatementErrors(t *testing.T) {
+	src := `
+architecture rtl of e is
+    function f(x : integer) return integer is
+    begin
+        if x > 0 then
+            return x
+        end if;
+        loop
+        end loop other;
+        case x is
+            when 0 => return 0;
+        end case ?;
+        return -x;
+    end function f;
+begin
+end;
+`
+	file, errs := parseSource(src, 0)
+	want := []string{
+		"expected ;, found END",
+		"closing label other of a statement without label",
+		"END CASE ? closes a case statement that is not matching",
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("got errors %v, want %d", errs, len(want))
+	}
+	for i, err := range errs {
+		if !strings.Contains(err.Msg, want[i]) {
+			t.Errorf("error %d: got %q, want %q", i, err.Msg, want[i])
+		}
+	}
+	architecture := file.DesignUnits[0].LibraryUnit.(ast.ArchitectureBody)
+	body := (*architecture.ArchitectureDeclarativePart.BlockDeclarativeItems)[0].(ast.SubprogramBody)
+	if len(body.SequentialStatements) != 4 {
+		t.Errorf("got %d statements, want all of them", len(body.SequentialStatements))
+	}
+}
+
+func parseExpr(src string, mode Mode) (ast.Expression, scanner.ErrorList) {
+	var p Parser
+	p.Init(token.NewFileSet(), "test.vhd", []byte(src), mode)
+	expression, _ := p.ParseExpr()
+	return expression, p.errors
+}
+
+// exprString renders the operators of an expression with explicit parentheses.
+func exprString(expression ast.Expression) string {
+	switch expression := expression.(type) {
+	case ast.BasicLit:
+		return expression.Value
+	case ast.SimpleName:
+		return expression.Identifier.Identifier
+	case ast.UnaryExpr:
+		return fmt.Sprintf("(%s %s)", expression.Op, exprString(expression.X))
+	case ast.BinaryExpr:
+		return fmt.Sprintf("(%s %s %s)", exprString(expression.X), expression.Op, exprString(expression.Y))
+	case ast.ParenExpr:
+		return exprString(expression.X)
+	}
+	return fmt.Sprintf("%T", expression)
+}
+
+func TestExpressionPrecedence(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"a + b * c", "(a + (b * c))"},
+		{"-a * b", "(- (a * b))"},
+		{"-a + b", "((- a) + b)"},
+		{"not a and b", "((NOT a) AND b)"},
+		{"a and b and c", "((a AND b) AND c)"},
+		{"abs a * 2", "((ABS a) * 2)"},
+		{"a * not b", "(a * (NOT b))"},
+		{"-a ** 2", "(- (a ** 2))"},
+		{"a & b + c", "((a & b) + c)"},
+		{"a sll 2 = b", "((a SLL 2) = b)"},
+		{"a = b or c /= d", "((a = b) OR (c /= d))"},
+		{"(a or b) and c", "((a OR b) AND c)"},
+		{"a - b - c", "((a - b) - c)"},
+		{"?? a", "(?? a)"},
+		{"and a", "(AND a)"},
+		{"a ?= b", "(a ?= b)"},
+	}
+	for _, test := range tests {
+		expression, errs := parseExpr(test.src, 0)
+		if len(errs) != 0 {
+			t.Errorf("%s: unexpected errors: %v", test.src, errs)
+			continue
+		}
+		if got := exprString(expression); got != test.want {
+			t.Errorf("%s: got %s, want %s", test.src, got, test.want)
+		}
+	}
+}
+
+func TestExpressionErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		mode Mode
+		want string
+	}{
+		{"a and b or c", 0, "AND and OR can not be mixed without parentheses"},
+		{"a nand b nand c", 0, "NAND can not follow NAND without parentheses"},
+		{"a = b = c", 0, "= can not follow = without parentheses"},
+		{"a ** b ** c", 0, "** can not follow ** without parentheses"},
+		{"a + -b", 0, "expected primary, found -"},
+		{"a ** not b", 0, "expected primary, found NOT"},
+		{"abs a ** 2", 0, "** can not follow ABS without parentheses"},
+		{"or a", VHDL93, "unary OR"},
+		{"a +", 0, "expected expression, found EOF"},
+		{"a b", 0, "expected end of expression, found IDENT"},
+		{"(others, a)", 0, "expected =>, found ,"},
+	}
+	for _, test := range tests {
+		_, errs := parseExpr(test.src, test.mode)
+		if len(errs) == 0 {
+			t.Errorf("%s: got no error, want %q", test.src, test.want)
+			continue
+		}
+		if !strings.Contains(errs[0].Msg, test.want) {
+			t.Errorf("%s: got %q, want %q", test.src, errs[0].Msg, test.want)
+		}
+	}
+}
+
+func TestPrimaries(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"16#FF#", "ast.BasicLit"},
+		{"10 ns", "ast.PhysicalLiteral"},
+		{`X"FF"`, "ast.BasicLit"},
+		{"null", "ast.BasicLit"},
+		{`"and"(a, b)`, "ast.IndexedName"},
+		{"f(a => 1)", "ast.FunctionCall"},
+		{"(others => '0')", "ast.Aggregate"},
+		{"(1, 2, 3)", "ast.Aggregate"},
+		{"(0 to 3 => '1', 4 | 5 => x)", "ast.Aggregate"},
+		{"std_logic_vector'(x)", "ast.QualifiedExpression"},
+		{"integer'(others => 0)", "ast.QualifiedExpression"},
+		{"new node", "ast.Allocator"},
+		{"new integer'(5)", "ast.Allocator"},
+		{"clk'event", "ast.AttributeName"},
+	}
+	for _, test := range tests {
+		expression, errs := parseExpr(test.src, 0)
+		if len(errs) != 0 {
+			t.Errorf("%s: unexpected errors: %v", test.src, errs)
+			continue
+		}
+		if got := fmt.Sprintf("%T", expression); got != test.want {
+			t.Errorf("%s: got %s, want %s", test.src, got, test.want)
+		}
+	}
+
+	expression, _ := parseExpr("(others => '0')", 0)
+	aggregate := expression.(ast.Aggregate)
+	if choice, ok := aggregate.ElementAssociations[0].Choices[0].(ast.Keyword); !ok || choice.Token != token.OTHERS {
+		t.Errorf("got choice %v, want OTHERS", aggregate.ElementAssociations[0].Choices)
+	}
+	expression, _ = parseExpr("new integer'(5)", 0)
+	if _, ok := expression.(ast.Allocator).Operand.(ast.QualifiedExpression); !ok {
+		t.Errorf("got allocator operand %T, want a qualified expression", expression.(ast.Allocator).Operand)
+	}
+}
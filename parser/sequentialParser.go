@@ -0,0 +1,900 @@
+package parser
+
+import (
+	"errors"
+	"vhdl/ast"
+	"vhdl/token"
+)
+
+// parseSequenceOfStatements parses the statements of a process, a subprogram body or a
+// compound statement up to the END that closes it, or up to the next alternative of an if
+// or a case statement. A statement that can not be parsed is skipped up to its semicolon
+// and the next one is parsed.
+func (p *Parser) parseSequenceOfStatements() ([]ast.SequentialStatement, error) {
+	var sequential_statements []ast.SequentialStatement
+	if p.trace {
+		defer un(trace(p, "SequenceOfStatements"))
+	}
+
+	for !isStatementsEnd(p.tok) {
+		sequential_statement, error := p.parseSequentialStatement()
+		if error != nil {
+			p.skipTo(token.SEMICOLON, token.END)
+			if p.tok == token.SEMICOLON {
+				p.next()
+			}
+			continue
+		}
+		sequential_statements = append(sequential_statements, sequential_statement)
+	}
+	return sequential_statements, nil
+}
+
+func (p *Parser) parseSequentialStatement() (ast.SequentialStatement, error) {
+	var sequential_statement ast.SequentialStatement
+	if p.trace {
+		defer un(trace(p, "SequentialStatement"))
+	}
+	pos := p.pos
+
+	var label *ast.Identifier
+	if p.tok == token.IDENT && p.tok2 == token.COLON {
+		identifier := p.identifier()
+		label = &identifier
+		p.next()
+		p.next()
+		if p.tok != token.WHILE && p.tok != token.FOR && p.tok != token.LOOP {
+			//Only loops could be labeled before VHDL-93
+			p.checkStandard(pos, token.VHDL93, "label of a sequential statement")
+		}
+	}
+
+	switch p.tok {
+	case token.WAIT:
+		return p.parseWaitStatement(label, pos)
+	case token.ASSERT:
+		assertion_statement := ast.AssertionStatement{Label: label, Node: ast.Node{Pos: pos}}
+		assertion, error := p.parseAssertion()
+		if error != nil {
+			return assertion_statement, errors.New("invalid assertion")
+		}
+		assertion_statement.Assertion = assertion
+		if p.expect(token.SEMICOLON) == token.NoPos {
+			return assertion_statement, errors.New("invalid assertion statement")
+		}
+		assertion_statement.End = p.prevEnd
+		return assertion_statement, nil
+	case token.REPORT:
+		return p.parseReportStatement(label, pos)
+	case token.IF:
+		return p.parseIfStatement(label, pos)
+	case token.CASE:
+		return p.parseCaseStatement(label, pos)
+	case token.WHILE, token.FOR, token.LOOP:
+		return p.parseLoopStatement(label, pos)
+	case token.NEXT, token.EXIT:
+		return p.parseNextOrExitStatement(label, pos)
+	case token.RETURN:
+		return p.parseReturnStatement(label, pos)
+	case token.NULL:
+		p.next()
+		null_statement := ast.NullStatement{Label: label, Node: ast.Node{Pos: pos}}
+		if p.expect(token.SEMICOLON) == token.NoPos {
+			return null_statement, errors.New("invalid null statement")
+		}
+		null_statement.End = p.prevEnd
+		return null_statement, nil
+	case token.WITH:
+		return p.parseSelectedAssignment(label, pos)
+	case token.IDENT:
+		return p.parseSequentialNameStatement(label, pos)
+	case token.LPAREN:
+		target, error := p.parseTarget()
+		if error != nil {
+			return sequential_statement, errors.New("invalid target")
+		}
+		switch p.tok {
+		case token.LEQ_SA:
+			return p.parseSignalAssignment(label, target, pos)
+		case token.VAR_ASSIGN:
+			return p.parseVariableAssignment(label, target, pos)
+		}
+		p.errorExpected(p.pos, "expected <= or :=, found %s", p.tok)
+	default:
+		p.errorExpected(p.pos, "expected sequential statement, found %s", p.tok)
+	}
+	return sequential_statement, errors.New("invalid sequential statement")
+}
+
+// parseSequentialNameStatement parses the statements that start with a name: a signal or
+// a variable assignment to the name or a call to the procedure it names.
+func (p *Parser) parseSequentialNameStatement(label *ast.Identifier, pos token.Pos) (ast.SequentialStatement, error) {
+	var sequential_statement ast.SequentialStatement
+	name_pos := p.pos
+	name, error := p.parseName()
+	if error != nil {
+		return sequential_statement, errors.New("invalid name")
+	}
+
+	switch p.tok {
+	case token.LEQ_SA:
+		return p.parseSignalAssignment(label, name, pos)
+	case token.VAR_ASSIGN:
+		return p.parseVariableAssignment(label, name, pos)
+	}
+
+	procedure_call_statement := ast.ProcedureCallStatement{Label: label, Node: ast.Node{Pos: pos}}
+	procedure_call_statement.ProcedureCall = ast.ProcedureCall{Name: name, Node: ast.Node{Pos: name_pos, End: p.prevEnd}}
+	if p.expect(token.SEMICOLON) == token.NoPos {
+		return procedure_call_statement, errors.New("invalid procedure call")
+	}
+	procedure_call_statement.End = p.prevEnd
+	return procedure_call_statement, nil
+}
+
+// parseSignalAssignment parses a simple, a conditional, a force or a release signal
+// assignment to target, from the <= delimiter.
+func (p *Parser) parseSignalAssignment(label *ast.Identifier, target ast.Name, pos token.Pos) (ast.SequentialStatement, error) {
+	var sequential_statement ast.SequentialStatement
+	if p.trace {
+		defer un(trace(p, "SignalAssignment"))
+	}
+	if p.expect(token.LEQ_SA) == token.NoPos {
+		return sequential_statement, errors.New("invalid signal assignment")
+	}
+
+	if p.tok == token.FORCE || p.tok == token.RELEASE {
+		force := p.tok == token.FORCE
+		p.next()
+		force_mode := token.ILLEGAL
+		if p.tok == token.IN || p.tok == token.OUT {
+			force_mode = p.tok
+			p.next()
+		}
+		if !force {
+			release_assignment := ast.SimpleReleaseAssignment{Label: label, Target: target, ForceMode: force_mode, Node: ast.Node{Pos: pos}}
+			if p.expect(token.SEMICOLON) == token.NoPos {
+				return release_assignment, errors.New("invalid release assignment")
+			}
+			release_assignment.End = p.prevEnd
+			return release_assignment, nil
+		}
+		expression_pos := p.pos
+		expression, error := p.parseExpression()
+		if error != nil {
+			return sequential_statement, errors.New("invalid expression")
+		}
+		if p.tok != token.WHEN {
+			force_assignment := ast.SimpleForceAssignment{Label: label, Target: target, ForceMode: force_mode, Expression: expression, Node: ast.Node{Pos: pos}}
+			if p.expect(token.SEMICOLON) == token.NoPos {
+				return force_assignment, errors.New("invalid force assignment")
+			}
+			force_assignment.End = p.prevEnd
+			return force_assignment, nil
+		}
+		conditional_assignment := ast.ConditionalForceAssignment{Label: label, Target: target, ForceMode: force_mode, Node: ast.Node{Pos: pos}}
+		conditional_expressions, error := p.parseConditionalExpressions(expression, expression_pos)
+		if error != nil {
+			return conditional_assignment, errors.New("invalid conditional expressions")
+		}
+		conditional_assignment.ConditionalExpressions = conditional_expressions
+		if p.expect(token.SEMICOLON) == token.NoPos {
+			return conditional_assignment, errors.New("invalid force assignment")
+		}
+		conditional_assignment.End = p.prevEnd
+		return conditional_assignment, nil
+	}
+
+	delay_mechanism, error := p.parseDelayMechanism()
+	if error != nil {
+		return sequential_statement, errors.New("invalid delay mechanism")
+	}
+	waveform, error := p.parseWaveform()
+	if error != nil {
+		return sequential_statement, errors.New("invalid waveform")
+	}
+
+	if p.tok != token.WHEN {
+		simple_assignment := ast.SimpleSignalAssignment{Label: label, Target: target, DelayMechanism: delay_mechanism, Waveform: waveform, Node: ast.Node{Pos: pos}}
+		if p.expect(token.SEMICOLON) == token.NoPos {
+			return simple_assignment, errors.New("invalid signal assignment")
+		}
+		simple_assignment.End = p.prevEnd
+		return simple_assignment, nil
+	}
+
+	p.checkStandard(p.pos, token.VHDL2008, "conditional signal assignment in a sequential statement")
+	conditional_assignment := ast.ConditionalSignalAssignment{Label: label, Target: target, DelayMechanism: delay_mechanism, Node: ast.Node{Pos: pos}}
+	conditional_waveforms, error := p.parseConditionalWaveforms(waveform)
+	if error != nil {
+		return conditional_assignment, errors.New("invalid conditional waveforms")
+	}
+	conditional_assignment.ConditionalWaveforms = conditional_waveforms
+	if p.expect(token.SEMICOLON) == token.NoPos {
+		return conditional_assignment, errors.New("invalid signal assignment")
+	}
+	conditional_assignment.End = p.prevEnd
+	return conditional_assignment, nil
+}
+
+// parseVariableAssignment parses a simple or a conditional variable assignment to
+// target, from the := delimiter.
+func (p *Parser) parseVariableAssignment(label *ast.Identifier, target ast.Name, pos token.Pos) (ast.SequentialStatement, error) {
+	var sequential_statement ast.SequentialStatement
+	if p.trace {
+		defer un(trace(p, "VariableAssignment"))
+	}
+	if p.expect(token.VAR_ASSIGN) == token.NoPos {
+		return sequential_statement, errors.New("invalid variable assignment")
+	}
+
+	expression_pos := p.pos
+	expression, error := p.parseExpression()
+	if error != nil {
+		return sequential_statement, errors.New("invalid expression")
+	}
+
+	if p.tok != token.WHEN {
+		variable_assignment := ast.VariableAssignment{Label: label, Target: target, Expression: expression, Node: ast.Node{Pos: pos}}
+		if p.expect(token.SEMICOLON) == token.NoPos {
+			return variable_assignment, errors.New("invalid variable assignment")
+		}
+		variable_assignment.End = p.prevEnd
+		return variable_assignment, nil
+	}
+
+	p.checkStandard(p.pos, token.VHDL2008, "conditional variable assignment")
+	conditional_assignment := ast.ConditionalVariableAssignment{Label: label, Target: target, Node: ast.Node{Pos: pos}}
+	conditional_expressions, error := p.parseConditionalExpressions(expression, expression_pos)
+	if error != nil {
+		return conditional_assignment, errors.New("invalid conditional expressions")
+	}
+	conditional_assignment.ConditionalExpressions = conditional_expressions
+	if p.expect(token.SEMICOLON) == token.NoPos {
+		return conditional_assignment, errors.New("invalid variable assignment")
+	}
+	conditional_assignment.End = p.prevEnd
+	return conditional_assignment, nil
+}
+
+// parseSelectedAssignment parses a VHDL-2008 selected signal, force or variable assignment
+// from the WITH keyword, the delimiter after the target tells them apart.
+func (p *Parser) parseSelectedAssignment(label *ast.Identifier, pos token.Pos) (ast.SequentialStatement, error) {
+	var sequential_statement ast.SequentialStatement
+	if p.trace {
+		defer un(trace(p, "SelectedAssignment"))
+	}
+	p.checkStandard(p.pos, token.VHDL2008, "selected assignment in a sequential statement")
+	if p.expect(token.WITH) == token.NoPos {
+		return sequential_statement, errors.New("invalid selected assignment")
+	}
+
+	expression, error := p.parseExpression()
+	if error != nil {
+		return sequential_statement, errors.New("invalid expression")
+	}
+	if p.expect(token.SELECT) == token.NoPos {
+		return sequential_statement, errors.New("invalid selected assignment")
+	}
+	matching := p.tok == token.QUEST
+	if matching {
+		p.next()
+	}
+	target, error := p.parseTarget()
+	if error != nil {
+		return sequential_statement, errors.New("invalid target")
+	}
+
+	switch p.tok {
+	case token.LEQ_SA:
+		p.next()
+		if p.tok == token.FORCE {
+			p.next()
+			force_assignment := ast.SelectedForceAssignment{Label: label, Expression: expression, Matching: matching, Target: target, ForceMode: token.ILLEGAL, Node: ast.Node{Pos: pos}}
+			if p.tok == token.IN || p.tok == token.OUT {
+				force_assignment.ForceMode = p.tok
+				p.next()
+			}
+			selected_expressions, error := p.parseSelectedExpressions()
+			if error != nil {
+				return force_assignment, errors.New("invalid selected expressions")
+			}
+			force_assignment.SelectedExpressions = selected_expressions
+			if p.expect(token.SEMICOLON) == token.NoPos {
+				return force_assignment, errors.New("invalid selected force assignment")
+			}
+			force_assignment.End = p.prevEnd
+			return force_assignment, nil
+		}
+		signal_assignment := ast.SelectedSignalAssignment{Label: label, Expression: expression, Matching: matching, Target: target, Node: ast.Node{Pos: pos}}
+		delay_mechanism, error := p.parseDelayMechanism()
+		if error != nil {
+			return signal_assignment, errors.New("invalid delay mechanism")
+		}
+		signal_assignment.DelayMechanism = delay_mechanism
+		selected_waveforms, error := p.parseSelectedWaveforms()
+		if error != nil {
+			return signal_assignment, errors.New("invalid selected waveforms")
+		}
+		signal_assignment.SelectedWaveforms = selected_waveforms
+		if p.expect(token.SEMICOLON) == token.NoPos {
+			return signal_assignment, errors.New("invalid selected signal assignment")
+		}
+		signal_assignment.End = p.prevEnd
+		return signal_assignment, nil
+	case token.VAR_ASSIGN:
+		p.next()
+		variable_assignment := ast.SelectedVariableAssignment{Label: label, Expression: expression, Matching: matching, Target: target, Node: ast.Node{Pos: pos}}
+		selected_expressions, error := p.parseSelectedExpressions()
+		if error != nil {
+			return variable_assignment, errors.New("invalid selected expressions")
+		}
+		variable_assignment.SelectedExpressions = selected_expressions
+		if p.expect(token.SEMICOLON) == token.NoPos {
+			return variable_assignment, errors.New("invalid selected variable assignment")
+		}
+		variable_assignment.End = p.prevEnd
+		return variable_assignment, nil
+	default:
+		p.errorExpected(p.pos, "expected <= or :=, found %s", p.tok)
+	}
+	return sequential_statement, errors.New("invalid selected assignment")
+}
+
+// parseConditionalExpressions parses the conditional expressions of an assignment from the
+// WHEN that follows the first expression, which starts at expression_pos.
+func (p *Parser) parseConditionalExpressions(expression ast.Expression, expression_pos token.Pos) ([]ast.ConditionalExpression, error) {
+	var conditional_expressions []ast.ConditionalExpression
+	for {
+		conditional_expression := ast.ConditionalExpression{Expression: expression, Node: ast.Node{Pos: expression_pos}}
+		if p.tok == token.WHEN {
+			p.next()
+			condition, error := p.parseExpression()
+			if error != nil {
+				return conditional_expressions, errors.New("invalid condition")
+			}
+			conditional_expression.Condition = condition
+		}
+		conditional_expression.End = p.prevEnd
+		conditional_expressions = append(conditional_expressions, conditional_expression)
+		if p.tok != token.ELSE || conditional_expression.Condition == nil {
+			break
+		}
+		p.next()
+		expression_pos = p.pos
+		next_expression, error := p.parseExpression()
+		if error != nil {
+			return conditional_expressions, errors.New("invalid expression")
+		}
+		expression = next_expression
+	}
+	return conditional_expressions, nil
+}
+
+// parseSelectedExpressions parses expression when choices { , expression when choices }.
+func (p *Parser) parseSelectedExpressions() ([]ast.SelectedExpression, error) {
+	var selected_expressions []ast.SelectedExpression
+	for {
+		selected_expression := ast.SelectedExpression{Node: ast.Node{Pos: p.pos}}
+		expression, error := p.parseExpression()
+		if error != nil {
+			return selected_expressions, errors.New("invalid expression")
+		}
+		selected_expression.Expression = expression
+		if p.expect(token.WHEN) == token.NoPos {
+			return selected_expressions, errors.New("invalid selected expression")
+		}
+		choices, error := p.parseChoices()
+		if error != nil {
+			return selected_expressions, errors.New("invalid choices")
+		}
+		selected_expression.Choices = choices
+		selected_expression.End = p.prevEnd
+		selected_expressions = append(selected_expressions, selected_expression)
+		if p.tok != token.COMMA {
+			break
+		}
+		p.next()
+	}
+	return selected_expressions, nil
+}
+
+// parseWaitStatement parses wait [ on sensitivity_list ] [ until condition ]
+// [ for time_expression ] ;
+func (p *Parser) parseWaitStatement(label *ast.Identifier, pos token.Pos) (ast.WaitStatement, error) {
+	wait_statement := ast.WaitStatement{Label: label, Node: ast.Node{Pos: pos}}
+	if p.trace {
+		defer un(trace(p, "WaitStatement"))
+	}
+	if p.expect(token.WAIT) == token.NoPos {
+		return wait_statement, errors.New("invalid wait statement")
+	}
+
+	if p.tok == token.ON {
+		p.next()
+		for {
+			name, error := p.parseName()
+			if error != nil {
+				return wait_statement, errors.New("invalid sensitivity clause")
+			}
+			wait_statement.SensitivityClause = append(wait_statement.SensitivityClause, name)
+			if p.tok != token.COMMA {
+				break
+			}
+			p.next()
+		}
+	}
+	if p.tok == token.UNTIL {
+		p.next()
+		condition, error := p.parseExpression()
+		if error != nil {
+			return wait_statement, errors.New("invalid condition clause")
+		}
+		wait_statement.ConditionClause = condition
+	}
+	if p.tok == token.FOR {
+		p.next()
+		timeout, error := p.parseExpression()
+		if error != nil {
+			return wait_statement, errors.New("invalid timeout clause")
+		}
+		wait_statement.TimeoutClause = timeout
+	}
+	if p.expect(token.SEMICOLON) == token.NoPos {
+		return wait_statement, errors.New("invalid wait statement")
+	}
+
+	wait_statement.End = p.prevEnd
+	return wait_statement, nil
+}
+
+// parseReportStatement parses report expression [ severity expression ] ;
+func (p *Parser) parseReportStatement(label *ast.Identifier, pos token.Pos) (ast.ReportStatement, error) {
+	report_statement := ast.ReportStatement{Label: label, Node: ast.Node{Pos: pos}}
+	p.checkStandard(p.pos, token.VHDL93, "REPORT statement")
+	if p.expect(token.REPORT) == token.NoPos {
+		return report_statement, errors.New("invalid report statement")
+	}
+
+	report, error := p.parseExpression()
+	if error != nil {
+		return report_statement, errors.New("invalid report expression")
+	}
+	report_statement.Report = report
+	if p.tok == token.SEVERITY {
+		p.next()
+		severity, error := p.parseExpression()
+		if error != nil {
+			return report_statement, errors.New("invalid severity expression")
+		}
+		report_statement.Severity = severity
+	}
+	if p.expect(token.SEMICOLON) == token.NoPos {
+		return report_statement, errors.New("invalid report statement")
+	}
+
+	report_statement.End = p.prevEnd
+	return report_statement, nil
+}
+
+func (p *Parser) parseIfStatement(label *ast.Identifier, pos token.Pos) (ast.IfStatement, error) {
+	if_statement := ast.IfStatement{Label: label, Node: ast.Node{Pos: pos}}
+	if p.trace {
+		defer un(trace(p, "IfStatement"))
+	}
+	if p.expect(token.IF) == token.NoPos {
+		return if_statement, errors.New("invalid if statement")
+	}
+
+	for {
+		alternative := ast.IfAlternative{Node: ast.Node{Pos: p.pos}}
+		condition, error := p.parseExpression()
+		if error != nil {
+			return if_statement, errors.New("invalid condition")
+		}
+		alternative.Condition = condition
+		if p.expect(token.THEN) == token.NoPos {
+			return if_statement, errors.New("invalid if statement")
+		}
+		sequential_statements, error := p.parseSequenceOfStatements()
+		if error != nil {
+			return if_statement, errors.New("invalid sequence of statements")
+		}
+		alternative.SequentialStatements = sequential_statements
+		alternative.End = p.prevEnd
+		if_statement.Alternatives = append(if_statement.Alternatives, alternative)
+
+		if p.tok != token.ELSIF {
+			break
+		}
+		p.next()
+	}
+
+	if p.tok == token.ELSE {
+		p.next()
+		alternative := ast.IfAlternative{Node: ast.Node{Pos: p.pos}}
+		sequential_statements, error := p.parseSequenceOfStatements()
+		if error != nil {
+			return if_statement, errors.New("invalid sequence of statements")
+		}
+		alternative.SequentialStatements = sequential_statements
+		alternative.End = p.prevEnd
+		if_statement.Alternatives = append(if_statement.Alternatives, alternative)
+	}
+
+	if p.expect(token.END) == token.NoPos || p.expect(token.IF) == token.NoPos {
+		return if_statement, errors.New("invalid if statement")
+	}
+	if_statement.IfLabel = p.parseClosingLabel(label)
+	if p.expect(token.SEMICOLON) == token.NoPos {
+		return if_statement, errors.New("invalid if statement")
+	}
+
+	if_statement.End = p.prevEnd
+	return if_statement, nil
+}
+
+// parseCaseStatement parses a case statement, case ? is the VHDL-2008 matching case.
+func (p *Parser) parseCaseStatement(label *ast.Identifier, pos token.Pos) (ast.CaseStatement, error) {
+	case_statement := ast.CaseStatement{Label: label, Node: ast.Node{Pos: pos}}
+	if p.trace {
+		defer un(trace(p, "CaseStatement"))
+	}
+	if p.expect(token.CASE) == token.NoPos {
+		return case_statement, errors.New("invalid case statement")
+	}
+	if p.tok == token.QUEST {
+		//The scanner reports ? before VHDL-2008
+		case_statement.Matching = true
+		p.next()
+	}
+
+	expression, error := p.parseExpression()
+	if error != nil {
+		return case_statement, errors.New("invalid expression")
+	}
+	case_statement.Expression = expression
+	if p.expect(token.IS) == token.NoPos {
+		return case_statement, errors.New("invalid case statement")
+	}
+
+	if p.tok != token.WHEN {
+		p.errorExpected(p.pos, "expected WHEN, found %s", p.tok)
+		return case_statement, errors.New("invalid case statement")
+	}
+	for p.tok == token.WHEN {
+		alternative := ast.CaseStatementAlternative{Node: ast.Node{Pos: p.pos}}
+		p.next()
+		choices, error := p.parseChoices()
+		if error != nil {
+			return case_statement, errors.New("invalid choices")
+		}
+		alternative.Choices = choices
+		if p.expect(token.ARROW) == token.NoPos {
+			return case_statement, errors.New("invalid case statement alternative")
+		}
+		sequential_statements, error := p.parseSequenceOfStatements()
+		if error != nil {
+			return case_statement, errors.New("invalid sequence of statements")
+		}
+		alternative.SequentialStatements = sequential_statements
+		alternative.End = p.prevEnd
+		case_statement.Alternatives = append(case_statement.Alternatives, alternative)
+	}
+
+	if p.expect(token.END) == token.NoPos || p.expect(token.CASE) == token.NoPos {
+		return case_statement, errors.New("invalid case statement")
+	}
+	if p.tok == token.QUEST {
+		if !case_statement.Matching {
+			p.error(p.pos, "END CASE ? closes a case statement that is not matching")
+		}
+		p.next()
+	}
+	case_statement.CaseLabel = p.parseClosingLabel(label)
+	if p.expect(token.SEMICOLON) == token.NoPos {
+		return case_statement, errors.New("invalid case statement")
+	}
+
+	case_statement.End = p.prevEnd
+	return case_statement, nil
+}
+
+// parseLoopStatement parses a while, a for or a plain loop.
+func (p *Parser) parseLoopStatement(label *ast.Identifier, pos token.Pos) (ast.LoopStatement, error) {
+	loop_statement := ast.LoopStatement{Label: label, Node: ast.Node{Pos: pos}}
+	if p.trace {
+		defer un(trace(p, "LoopStatement"))
+	}
+
+	switch p.tok {
+	case token.WHILE:
+		while_scheme := ast.WhileScheme{Node: ast.Node{Pos: p.pos}}
+		p.next()
+		condition, error := p.parseExpression()
+		if error != nil {
+			return loop_statement, errors.New("invalid condition")
+		}
+		while_scheme.Condition = condition
+		while_scheme.End = p.prevEnd
+		loop_statement.IterationScheme = while_scheme
+	case token.FOR:
+		p.next()
+		parameter_specification, error := p.parseParameterSpecification()
+		if error != nil {
+			return loop_statement, errors.New("invalid parameter specification")
+		}
+		loop_statement.IterationScheme = parameter_specification
+	}
+
+	if p.expect(token.LOOP) == token.NoPos {
+		return loop_statement, errors.New("invalid loop statement")
+	}
+	sequential_statements, error := p.parseSequenceOfStatements()
+	if error != nil {
+		return loop_statement, errors.New("invalid sequence of statements")
+	}
+	loop_statement.SequentialStatements = sequential_statements
+
+	if p.expect(token.END) == token.NoPos || p.expect(token.LOOP) == token.NoPos {
+		return loop_statement, errors.New("invalid loop statement")
+	}
+	loop_statement.LoopLabel = p.parseClosingLabel(label)
+	if p.expect(token.SEMICOLON) == token.NoPos {
+		return loop_statement, errors.New("invalid loop statement")
+	}
+
+	loop_statement.End = p.prevEnd
+	return loop_statement, nil
+}
+
+// parseNextOrExitStatement parses next or exit [ loop_label ] [ when condition ] ;
+func (p *Parser) parseNextOrExitStatement(label *ast.Identifier, pos token.Pos) (ast.SequentialStatement, error) {
+	kind := p.tok
+	p.next()
+
+	var loop_label *ast.SimpleName
+	if p.tok == token.IDENT {
+		loop_label = &ast.SimpleName{Identifier: p.identifier()}
+		p.next()
+	}
+	var condition ast.Expression
+	if p.tok == token.WHEN {
+		p.next()
+		when, error := p.parseExpression()
+		if error != nil {
+			return nil, errors.New("invalid condition")
+		}
+		condition = when
+	}
+	if p.expect(token.SEMICOLON) == token.NoPos {
+		return nil, errors.New("invalid " + kind.String() + " statement")
+	}
+
+	node := ast.Node{Pos: pos, End: p.prevEnd}
+	if kind == token.NEXT {
+		return ast.NextStatement{Label: label, LoopLabel: loop_label, Condition: condition, Node: node}, nil
+	}
+	return ast.ExitStatement{Label: label, LoopLabel: loop_label, Condition: condition, Node: node}, nil
+}
+
+func (p *Parser) parseReturnStatement(label *ast.Identifier, pos token.Pos) (ast.ReturnStatement, error) {
+	return_statement := ast.ReturnStatement{Label: label, Node: ast.Node{Pos: pos}}
+	if p.expect(token.RETURN) == token.NoPos {
+		return return_statement, errors.New("invalid return statement")
+	}
+	if p.tok != token.SEMICOLON {
+		expression, error := p.parseExpression()
+		if error != nil {
+			return return_statement, errors.New("invalid expression")
+		}
+		return_statement.Expression = expression
+	}
+	if p.expect(token.SEMICOLON) == token.NoPos {
+		return return_statement, errors.New("invalid return statement")
+	}
+
+	return_statement.End = p.prevEnd
+	return return_statement, nil
+}
+
+// parseAssertion parses assert condition [ report expression ] [ severity expression ].
+func (p *Parser) parseAssertion() (ast.Assertion, error) {
+	assertion := ast.Assertion{Node: ast.Node{Pos: p.pos}}
+	if p.trace {
+		defer un(trace(p, "Assertion"))
+	}
+	if p.expect(token.ASSERT) == token.NoPos {
+		return assertion, errors.New("invalid assertion")
+	}
+
+	condition, error := p.parseExpression()
+	if error != nil {
+		return assertion, errors.New("invalid condition")
+	}
+	assertion.Condition = condition
+
+	if p.tok == token.REPORT {
+		p.next()
+		report, error := p.parseExpression()
+		if error != nil {
+			return assertion, errors.New("invalid report expression")
+		}
+		assertion.Report = report
+	}
+	if p.tok == token.SEVERITY {
+		p.next()
+		severity, error := p.parseExpression()
+		if error != nil {
+			return assertion, errors.New("invalid severity expression")
+		}
+		assertion.Severity = severity
+	}
+
+	assertion.End = p.prevEnd
+	return assertion, nil
+}
+
+// parseDelayMechanism parses transport or [ reject time_expression ] inertial, it
+// returns nil if there is no delay mechanism.
+func (p *Parser) parseDelayMechanism() (*ast.DelayMechanism, error) {
+	delay_mechanism := &ast.DelayMechanism{Node: ast.Node{Pos: p.pos}}
+	switch p.tok {
+	case token.TRANSPORT:
+		delay_mechanism.Transport = true
+		p.next()
+	case token.REJECT:
+		p.next()
+		reject_time, error := p.parseExpression()
+		if error != nil {
+			return delay_mechanism, errors.New("invalid reject time")
+		}
+		delay_mechanism.RejectTime = reject_time
+		if p.expect(token.INERTIAL) == token.NoPos {
+			return delay_mechanism, errors.New("invalid delay mechanism")
+		}
+	case token.INERTIAL:
+		p.next()
+	default:
+		return nil, nil
+	}
+	delay_mechanism.End = p.prevEnd
+	return delay_mechanism, nil
+}
+
+// parseWaveform parses waveform_element { , waveform_element } or unaffected.
+func (p *Parser) parseWaveform() (ast.Waveform, error) {
+	waveform := ast.Waveform{Node: ast.Node{Pos: p.pos}}
+	if p.trace {
+		defer un(trace(p, "Waveform"))
+	}
+	if p.tok == token.UNAFFECTED {
+		waveform.Unaffected = true
+		p.next()
+		waveform.End = p.prevEnd
+		return waveform, nil
+	}
+
+	for {
+		waveform_element := ast.WaveformElement{Node: ast.Node{Pos: p.pos}}
+		value, error := p.parseExpression()
+		if error != nil {
+			return waveform, errors.New("invalid waveform element")
+		}
+		waveform_element.Value = value
+		if p.tok == token.AFTER {
+			p.next()
+			after, error := p.parseExpression()
+			if error != nil {
+				return waveform, errors.New("invalid waveform element")
+			}
+			waveform_element.After = after
+		}
+		waveform_element.End = p.prevEnd
+		waveform.WaveformElements = append(waveform.WaveformElements, waveform_element)
+		if p.tok != token.COMMA {
+			break
+		}
+		p.next()
+	}
+
+	waveform.End = p.prevEnd
+	return waveform, nil
+}
+
+// parseConditionalWaveforms parses the conditional waveforms of an assignment from the
+// WHEN that follows the first waveform.
+func (p *Parser) parseConditionalWaveforms(waveform ast.Waveform) ([]ast.ConditionalWaveform, error) {
+	var conditional_waveforms []ast.ConditionalWaveform
+	for {
+		conditional_waveform := ast.ConditionalWaveform{Waveform: waveform, Node: ast.Node{Pos: waveform.Pos}}
+		if p.tok == token.WHEN {
+			p.next()
+			condition, error := p.parseExpression()
+			if error != nil {
+				return conditional_waveforms, errors.New("invalid condition")
+			}
+			conditional_waveform.Condition = condition
+		}
+		conditional_waveform.End = p.prevEnd
+		conditional_waveforms = append(conditional_waveforms, conditional_waveform)
+		if p.tok != token.ELSE || conditional_waveform.Condition == nil {
+			break
+		}
+		p.next()
+		next_waveform, error := p.parseWaveform()
+		if error != nil {
+			return conditional_waveforms, errors.New("invalid waveform")
+		}
+		waveform = next_waveform
+	}
+	return conditional_waveforms, nil
+}
+
+// parseSelectedWaveforms parses waveform when choices { , waveform when choices }.
+func (p *Parser) parseSelectedWaveforms() ([]ast.SelectedWaveform, error) {
+	var selected_waveforms []ast.SelectedWaveform
+	for {
+		waveform, error := p.parseWaveform()
+		if error != nil {
+			return selected_waveforms, errors.New("invalid waveform")
+		}
+		selected_waveform := ast.SelectedWaveform{Waveform: waveform, Node: ast.Node{Pos: waveform.Pos}}
+		if p.expect(token.WHEN) == token.NoPos {
+			return selected_waveforms, errors.New("invalid selected waveform")
+		}
+		choices, error := p.parseChoices()
+		if error != nil {
+			return selected_waveforms, errors.New("invalid choices")
+		}
+		selected_waveform.Choices = choices
+		selected_waveform.End = p.prevEnd
+		selected_waveforms = append(selected_waveforms, selected_waveform)
+		if p.tok != token.COMMA {
+			break
+		}
+		p.next()
+	}
+	return selected_waveforms, nil
+}
+
+// parseChoices parses choice { | choice }, a choice is an expression, a discrete range
+// or OTHERS.
+func (p *Parser) parseChoices() ([]ast.Choice, error) {
+	var choices []ast.Choice
+	if p.trace {
+		defer un(trace(p, "Choices"))
+	}
+	for {
+		if p.tok == token.OTHERS {
+			choices = append(choices, ast.Keyword{Token: p.tok, Value: p.lit})
+			p.next()
+		} else {
+			choice, error := p.parseDiscreteRange()
+			if error != nil {
+				return choices, errors.New("invalid choice")
+			}
+			choices = append(choices, choice)
+		}
+		if p.tok != token.VLINE {
+			break
+		}
+		p.next()
+	}
+	return choices, nil
+}
+
+// parseParameterSpecification parses identifier in discrete_range.
+func (p *Parser) parseParameterSpecification() (ast.ParameterSpecification, error) {
+	parameter_specification := ast.ParameterSpecification{Identifier: p.identifier(), Node: ast.Node{Pos: p.pos}}
+	if p.expect(token.IDENT) == token.NoPos || p.expect(token.IN) == token.NoPos {
+		return parameter_specification, errors.New("invalid parameter specification")
+	}
+	discrete_range, error := p.parseDiscreteRange()
+	if error != nil {
+		return parameter_specification, errors.New("invalid discrete range")
+	}
+	parameter_specification.DiscreteRange = discrete_range
+	parameter_specification.End = p.prevEnd
+	return parameter_specification, nil
+}
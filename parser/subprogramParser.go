@@ -0,0 +1,228 @@
+package parser
+
+import (
+	"errors"
+	"vhdl/ast"
+	"vhdl/token"
+)
+
+func (p *Parser) parseSubprogramSpecification() (ast.SubprogramSpecification, error) {
+	var subprogram_specification ast.SubprogramSpecification
+	if p.trace {
+		defer un(trace(p, "SubprogramSpecification"))
+	}
+	pos := p.pos
+
+	purity := token.ILLEGAL
+	if p.tok == token.PURE || p.tok == token.IMPURE {
+		purity = p.tok
+		p.next()
+		if p.tok != token.FUNCTION {
+			p.errorExpected(p.pos, "expected FUNCTION, found %s", p.tok)
+			return subprogram_specification, errors.New("invalid subprogram specification")
+		}
+	}
+
+	kind := p.tok
+	if kind != token.PROCEDURE && kind != token.FUNCTION {
+		p.errorExpected(p.pos, "expected PROCEDURE or FUNCTION, found %s", p.tok)
+		return subprogram_specification, errors.New("invalid subprogram specification")
+	}
+	p.next()
+
+	designator, error := p.parseDesignator(kind)
+	if error != nil {
+		return subprogram_specification, errors.New("invalid designator")
+	}
+
+	var formal_parameter_list *ast.InterfaceList
+	if p.tok == token.PARAMETER {
+		p.checkStandard(p.pos, token.VHDL2019, "PARAMETER keyword")
+		p.next()
+	}
+	if p.tok == token.LPAREN {
+		parameter_list, error := p.parseInterfaceList(parameterInterface)
+		if error != nil {
+			return subprogram_specification, errors.New("invalid formal parameter list")
+		}
+		formal_parameter_list = &parameter_list
+	}
+
+	if kind == token.PROCEDURE {
+		return ast.ProcedureSpecification{Designator: designator, FormalParameterList: formal_parameter_list, Node: ast.Node{Pos: pos, End: p.prevEnd}}, nil
+	}
+
+	function_specification := ast.FunctionSpecification{Purity: purity, Designator: designator, FormalParameterList: formal_parameter_list, Node: ast.Node{Pos: pos}}
+	if p.expect(token.RETURN) == token.NoPos {
+		return function_specification, errors.New("invalid function specification")
+	}
+	return_type, error := p.parseTypeMark()
+	if error != nil {
+		return function_specification, errors.New("invalid return type")
+	}
+	function_specification.ReturnType = return_type
+	function_specification.End = p.prevEnd
+	return function_specification, nil
+}
+
+// parseDesignator parses the designator of a subprogram, an identifier or, for a
+// function, an operator symbol such as "+".
+func (p *Parser) parseDesignator(kind token.Token) (ast.Designator, error) {
+	var designator ast.Designator
+	switch {
+	case p.tok == token.IDENT:
+		designator = p.identifier()
+	case p.tok == token.STRING && kind == token.FUNCTION:
+		designator = ast.OperatorSymbol{Symbol: p.lit}
+	default:
+		p.errorExpected(p.pos, "expected designator, found %s", p.tok)
+		return designator, errors.New("invalid designator")
+	}
+	p.next()
+	return designator, nil
+}
+
+// parseSubprogramDeclarationOrBody parses a subprogram specification followed by a
+// semicolon, the declaration, or by IS, the body.
+func (p *Parser) parseSubprogramDeclarationOrBody() (ast.BlockDeclarativeItem, error) {
+	var declarative_item ast.BlockDeclarativeItem
+	if p.trace {
+		defer un(trace(p, "Subprogram"))
+	}
+	pos := p.pos
+
+	if p.isSubprogramInstantiation() {
+		return p.parseSubprogramInstantiationDeclaration()
+	}
+	subprogram_specification, error := p.parseSubprogramSpecification()
+	if error != nil {
+		return declarative_item, errors.New("invalid subprogram specification")
+	}
+
+	if p.tok == token.SEMICOLON {
+		p.next()
+		return ast.SubprogramDeclaration{SubprogramSpecification: subprogram_specification, Node: ast.Node{Pos: pos, End: p.prevEnd}}, nil
+	}
+
+	subprogram_body := ast.SubprogramBody{SubprogramSpecification: subprogram_specification, Node: ast.Node{Pos: pos}}
+	if p.expect(token.IS) == token.NoPos {
+		return subprogram_body, errors.New("invalid subprogram body")
+	}
+
+	declarative_items, error := p.parseDeclarativePart()
+	if error != nil {
+		return subprogram_body, errors.New("invalid subprogram declarative part")
+	}
+	subprogram_body.SubprogramDeclarativeItems = declarative_items
+
+	if p.expect(token.BEGIN) == token.NoPos {
+		return subprogram_body, errors.New("invalid subprogram body")
+	}
+	sequential_statements, error := p.parseSequenceOfStatements()
+	if error != nil {
+		return subprogram_body, errors.New("invalid subprogram statement part")
+	}
+	subprogram_body.SequentialStatements = sequential_statements
+
+	if p.expect(token.END) == token.NoPos {
+		return subprogram_body, errors.New("invalid subprogram body")
+	}
+	kind, designator := token.FUNCTION, ast.Designator(nil)
+	switch specification := subprogram_specification.(type) {
+	case ast.ProcedureSpecification:
+		kind, designator = token.PROCEDURE, specification.Designator
+	case ast.FunctionSpecification:
+		designator = specification.Designator
+	}
+	if p.tok == token.PROCEDURE || p.tok == token.FUNCTION {
+		p.checkStandard(p.pos, token.VHDL93, "END "+p.tok.String())
+		if p.tok != kind {
+			p.errorExpected(p.pos, "expected %s, found %s", kind, p.tok)
+		}
+		p.next()
+	}
+	if p.tok == token.IDENT || p.tok == token.STRING {
+		if !token.EqualIdent(designatorString(designator), p.lit) {
+			p.errorExpected(p.pos, "expected %s, found %s", designatorString(designator), p.lit)
+		}
+		subprogram_body.Designator, _ = p.parseDesignator(kind)
+	}
+
+	if p.expect(token.SEMICOLON) == token.NoPos {
+		return subprogram_body, errors.New("invalid subprogram body")
+	}
+	subprogram_body.End = p.prevEnd
+	return subprogram_body, nil
+}
+
+// isSubprogramInstantiation reports whether the subprogram at the current token is an
+// instantiation, procedure or function designator followed by is new.
+func (p *Parser) isSubprogramInstantiation() bool {
+	if p.tok != token.PROCEDURE && p.tok != token.FUNCTION {
+		return false
+	}
+	c := p.checkpoint()
+	defer p.restore(c)
+	kind := p.tok
+	p.next()
+	_, error := p.parseDesignator(kind)
+	return error == nil && p.tok == token.IS && p.tok2 == token.NEW
+}
+
+// parseSubprogramInstantiationDeclaration parses subprogram_kind designator is new
+// uninstantiated_subprogram_name [ signature ] [ generic_map_aspect ] ;
+func (p *Parser) parseSubprogramInstantiationDeclaration() (ast.SubprogramInstantiationDeclaration, error) {
+	subprogram_instantiation := ast.SubprogramInstantiationDeclaration{Kind: p.tok, Node: ast.Node{Pos: p.pos}}
+	if p.trace {
+		defer un(trace(p, "SubprogramInstantiationDeclaration"))
+	}
+	p.checkStandard(p.pos, token.VHDL2008, "subprogram instantiation")
+	p.next()
+
+	designator, error := p.parseDesignator(subprogram_instantiation.Kind)
+	if error != nil {
+		return subprogram_instantiation, errors.New("invalid designator")
+	}
+	subprogram_instantiation.Designator = designator
+	if p.expect(token.IS) == token.NoPos || p.expect(token.NEW) == token.NoPos {
+		return subprogram_instantiation, errors.New("invalid subprogram instantiation")
+	}
+
+	uninstantiated_subprogram_name, error := p.parseTypeMark()
+	if error != nil {
+		return subprogram_instantiation, errors.New("invalid subprogram name")
+	}
+	subprogram_instantiation.UninstantiatedSubprogramName = uninstantiated_subprogram_name
+
+	if p.tok == token.LSQPAREN {
+		signature, error := p.parseSignature()
+		if error != nil {
+			return subprogram_instantiation, errors.New("invalid signature")
+		}
+		subprogram_instantiation.Signature = &signature
+	}
+	if p.tok == token.GENERIC {
+		generic_map_aspect, error := p.parseGenericMapAspect(false)
+		if error != nil {
+			return subprogram_instantiation, errors.New("invalid generic map aspect")
+		}
+		subprogram_instantiation.GenericMapAspect = &generic_map_aspect
+	}
+
+	if p.expect(token.SEMICOLON) == token.NoPos {
+		return subprogram_instantiation, errors.New("invalid subprogram instantiation")
+	}
+	subprogram_instantiation.End = p.prevEnd
+	return subprogram_instantiation, nil
+}
+
+// designatorString returns the spelling of an identifier or an operator symbol.
+func designatorString(designator ast.Designator) string {
+	switch designator := designator.(type) {
+	case ast.Identifier:
+		return designator.Identifier
+	case ast.OperatorSymbol:
+		return designator.Symbol
+	}
+	return ""
+}
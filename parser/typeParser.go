@@ -0,0 +1,499 @@
+package parser
+
+import (
+	"errors"
+	"vhdl/ast"
+	"vhdl/token"
+)
+
+func (p *Parser) parseSubtypeIndication() (ast.SubtypeIndication, error) {
+	var subtype_indication ast.SubtypeIndication
+	if p.trace {
+		defer un(trace(p, "SubtypeIndication"))
+	}
+	subtype_indication.Pos = p.pos
+
+	if p.tok == token.LPAREN {
+		element_resolution, error := p.parseElementResolution()
+		if error != nil {
+			return subtype_indication, errors.New("invalid resolution indication")
+		}
+		subtype_indication.ResolutionIndication = element_resolution
+	}
+
+	type_mark, error := p.parseTypeMark()
+	if error != nil {
+		return subtype_indication, errors.New("invalid type mark")
+	}
+	if subtype_indication.ResolutionIndication == nil && p.tok == token.IDENT {
+		//The first name was the name of a resolution function
+		subtype_indication.ResolutionIndication = type_mark
+		type_mark, error = p.parseTypeMark()
+		if error != nil {
+			return subtype_indication, errors.New("invalid type mark")
+		}
+	}
+	subtype_indication.TypeMark = type_mark
+
+	if p.tok == token.RANGE || p.tok == token.LPAREN {
+		constraint, error := p.parseConstraint()
+		if error != nil {
+			return subtype_indication, errors.New("invalid constraint")
+		}
+		subtype_indication.Constraint = constraint
+	}
+
+	subtype_indication.End = p.prevEnd
+	return subtype_indication, nil
+}
+
+// parseElementResolution parses the resolution indication of the elements of an array
+// subtype, such as (resolved) in (resolved) std_ulogic_vector.
+func (p *Parser) parseElementResolution() (ast.ElementResolution, error) {
+	element_resolution := ast.ElementResolution{Node: ast.Node{Pos: p.pos}}
+	if p.expect(token.LPAREN) == token.NoPos {
+		return element_resolution, errors.New("invalid element resolution")
+	}
+
+	if p.tok == token.LPAREN {
+		resolution_indication, error := p.parseElementResolution()
+		if error != nil {
+			return element_resolution, errors.New("invalid element resolution")
+		}
+		element_resolution.ResolutionIndication = resolution_indication
+	} else {
+		resolution_function_name, error := p.parseTypeMark()
+		if error != nil {
+			return element_resolution, errors.New("invalid element resolution")
+		}
+		element_resolution.ResolutionIndication = resolution_function_name
+	}
+
+	if p.expect(token.RPAREN) == token.NoPos {
+		return element_resolution, errors.New("invalid element resolution")
+	}
+	element_resolution.End = p.prevEnd
+	return element_resolution, nil
+}
+
+func (p *Parser) parseConstraint() (ast.Constraint, error) {
+	var constraint ast.Constraint
+	if p.trace {
+		defer un(trace(p, "Constraint"))
+	}
+	switch p.tok {
+	case token.RANGE:
+		return p.parseRangeConstraint()
+	case token.LPAREN:
+		return p.parseIndexConstraint()
+	default:
+		p.errorExpected(p.pos, "expected constraint, found %s", p.tok)
+	}
+	return constraint, errors.New("invalid constraint")
+}
+
+func (p *Parser) parseRangeConstraint() (ast.RangeConstraint, error) {
+	range_constraint := ast.RangeConstraint{Node: ast.Node{Pos: p.pos}}
+	if p.expect(token.RANGE) == token.NoPos {
+		return range_constraint, errors.New("invalid range constraint")
+	}
+
+	range_, error := p.parseRange()
+	if error != nil {
+		return range_constraint, errors.New("invalid range")
+	}
+	range_constraint.Range = range_
+	range_constraint.End = p.prevEnd
+	return range_constraint, nil
+}
+
+func (p *Parser) parseIndexConstraint() (ast.IndexConstraint, error) {
+	index_constraint := ast.IndexConstraint{Node: ast.Node{Pos: p.pos}}
+	if p.trace {
+		defer un(trace(p, "IndexConstraint"))
+	}
+	if p.expect(token.LPAREN) == token.NoPos {
+		return index_constraint, errors.New("invalid index constraint")
+	}
+
+	for {
+		discrete_range, error := p.parseDiscreteRange()
+		if error != nil {
+			return index_constraint, errors.New("invalid discrete range")
+		}
+		index_constraint.DiscreteRanges = append(index_constraint.DiscreteRanges, discrete_range)
+		if p.tok != token.COMMA {
+			break
+		}
+		p.next()
+	}
+
+	if p.expect(token.RPAREN) == token.NoPos {
+		return index_constraint, errors.New("invalid index constraint")
+	}
+
+	if p.tok == token.LPAREN {
+		//Constraint of the elements of an array of arrays
+		element_constraint, error := p.parseIndexConstraint()
+		if error != nil {
+			return index_constraint, errors.New("invalid element constraint")
+		}
+		index_constraint.ElementConstraint = element_constraint
+	}
+
+	index_constraint.End = p.prevEnd
+	return index_constraint, nil
+}
+
+func (p *Parser) parseDiscreteRange() (ast.DiscreteRange, error) {
+	if p.trace {
+		defer un(trace(p, "DiscreteRange"))
+	}
+	switch {
+	case p.tok == token.OPEN:
+		//The index range is left open in the subtype indication
+		open := ast.Keyword{Token: p.tok, Value: p.lit}
+		p.next()
+		return open, nil
+	case p.tok == token.IDENT && p.tok2 == token.RANGE:
+		//A subtype indication with a range constraint, e.g. natural range 0 to 7
+		return p.parseSubtypeIndication()
+	}
+	return p.parseRange()
+}
+
+// parseRange parses a simple range, or a range attribute name which is returned as an expression.
+func (p *Parser) parseRange() (ast.Range, error) {
+	if p.trace {
+		defer un(trace(p, "Range"))
+	}
+	pos := p.pos
+
+	left, error := p.parseExpression()
+	if error != nil {
+		return left, errors.New("invalid range")
+	}
+	if p.tok != token.TO && p.tok != token.DOWNTO {
+		return left, nil
+	}
+
+	simple_range := ast.SimpleRange{Left: left, Direction: p.tok, Node: ast.Node{Pos: pos}}
+	p.next()
+	right, error := p.parseExpression()
+	if error != nil {
+		return simple_range, errors.New("invalid range")
+	}
+	simple_range.Right = right
+	simple_range.End = p.prevEnd
+	return simple_range, nil
+}
+
+func (p *Parser) parseTypeDeclaration() (ast.TypeDeclaration, error) {
+	var type_declaration ast.TypeDeclaration
+	if p.trace {
+		defer un(trace(p, "TypeDeclaration"))
+	}
+	pos := p.pos
+	if p.expect(token.TYPE) == token.NoPos {
+		return type_declaration, errors.New("invalid type declaration")
+	}
+	identifier := p.identifier()
+	if p.expect(token.IDENT) == token.NoPos {
+		return type_declaration, errors.New("invalid type declaration")
+	}
+
+	if p.tok == token.SEMICOLON {
+		p.next()
+		return ast.IncompleteTypeDeclaration{Identifier: identifier, Node: ast.Node{Pos: pos, End: p.prevEnd}}, nil
+	}
+
+	full_type_declaration := ast.FullTypeDeclaration{Identifier: identifier, Node: ast.Node{Pos: pos}}
+	if p.expect(token.IS) == token.NoPos {
+		return full_type_declaration, errors.New("invalid type declaration")
+	}
+	type_definition, error := p.parseTypeDefinition(identifier)
+	if error != nil {
+		return full_type_declaration, errors.New("invalid type definition")
+	}
+	full_type_declaration.TypeDefinition = type_definition
+
+	if p.expect(token.SEMICOLON) == token.NoPos {
+		return full_type_declaration, errors.New("invalid type declaration")
+	}
+	full_type_declaration.End = p.prevEnd
+	return full_type_declaration, nil
+}
+
+// parseTypeDefinition parses the definition of the type identifier, the identifier is
+// used to check the simple name at the end of a definition.
+func (p *Parser) parseTypeDefinition(identifier ast.Identifier) (ast.TypeDefinition, error) {
+	var type_definition ast.TypeDefinition
+	if p.trace {
+		defer un(trace(p, "TypeDefinition"))
+	}
+	switch p.tok {
+	case token.LPAREN:
+		return p.parseEnumerationTypeDefinition()
+	case token.RANGE:
+		pos := p.pos
+		range_constraint, error := p.parseRangeConstraint()
+		if error != nil {
+			return type_definition, errors.New("invalid range constraint")
+		}
+		if p.tok == token.UNITS {
+			return p.parsePhysicalTypeDefinition(range_constraint, identifier)
+		}
+		return ast.RangeTypeDefinition{RangeConstraint: range_constraint, Node: ast.Node{Pos: pos, End: p.prevEnd}}, nil
+	case token.ARRAY:
+		return p.parseArrayTypeDefinition()
+	case token.RECORD:
+		return p.parseRecordTypeDefinition(identifier)
+	case token.ACCESS:
+		pos := p.pos
+		p.next()
+		subtype_indication, error := p.parseSubtypeIndication()
+		if error != nil {
+			return type_definition, errors.New("invalid subtype indication")
+		}
+		return ast.AccessTypeDefinition{SubtypeIndication: subtype_indication, Node: ast.Node{Pos: pos, End: p.prevEnd}}, nil
+	case token.FILE:
+		pos := p.pos
+		p.next()
+		if p.expect(token.OF) == token.NoPos {
+			return type_definition, errors.New("invalid file type definition")
+		}
+		type_mark, error := p.parseTypeMark()
+		if error != nil {
+			return type_definition, errors.New("invalid type mark")
+		}
+		return ast.FileTypeDefinition{TypeMark: type_mark, Node: ast.Node{Pos: pos, End: p.prevEnd}}, nil
+	case token.PROTECTED:
+		return p.parseProtectedTypeDefinition(identifier)
+	default:
+		p.errorExpected(p.pos, "expected type definition, found %s", p.tok)
+	}
+	return type_definition, errors.New("invalid type definition")
+}
+
+func (p *Parser) parseEnumerationTypeDefinition() (ast.EnumerationTypeDefinition, error) {
+	enumeration := ast.EnumerationTypeDefinition{Node: ast.Node{Pos: p.pos}}
+	if p.expect(token.LPAREN) == token.NoPos {
+		return enumeration, errors.New("invalid enumeration type definition")
+	}
+
+	for {
+		switch p.tok {
+		case token.IDENT:
+			enumeration.EnumerationLiterals = append(enumeration.EnumerationLiterals, p.identifier())
+		case token.CHAR:
+			enumeration.EnumerationLiterals = append(enumeration.EnumerationLiterals, ast.CharacterLiteral{GraphicCharacter: ast.GraphicCharacter{Character: p.lit}})
+		default:
+			p.errorExpected(p.pos, "expected enumeration literal, found %s", p.tok)
+			return enumeration, errors.New("invalid enumeration literal")
+		}
+		p.next()
+		if p.tok != token.COMMA {
+			break
+		}
+		p.next()
+	}
+
+	if p.expect(token.RPAREN) == token.NoPos {
+		return enumeration, errors.New("invalid enumeration type definition")
+	}
+	enumeration.End = p.prevEnd
+	return enumeration, nil
+}
+
+func (p *Parser) parsePhysicalTypeDefinition(range_constraint ast.RangeConstraint, identifier ast.Identifier) (ast.PhysicalTypeDefinition, error) {
+	physical := ast.PhysicalTypeDefinition{RangeConstraint: range_constraint, Node: ast.Node{Pos: range_constraint.Pos}}
+	if p.trace {
+		defer un(trace(p, "PhysicalTypeDefinition"))
+	}
+	if p.expect(token.UNITS) == token.NoPos {
+		return physical, errors.New("invalid physical type definition")
+	}
+
+	physical.PrimaryUnitDeclaration = p.identifier()
+	if p.expect(token.IDENT) == token.NoPos || p.expect(token.SEMICOLON) == token.NoPos {
+		return physical, errors.New("invalid primary unit declaration")
+	}
+
+	for p.tok == token.IDENT {
+		secondary_unit := ast.SecondaryUnitDeclaration{Identifier: p.identifier(), Node: ast.Node{Pos: p.pos}}
+		p.next()
+		if p.expect(token.EQL) == token.NoPos {
+			return physical, errors.New("invalid secondary unit declaration")
+		}
+		physical_literal, error := p.parseExpression()
+		if error != nil {
+			return physical, errors.New("invalid physical literal")
+		}
+		secondary_unit.PhysicalLiteral = physical_literal
+		if p.expect(token.SEMICOLON) == token.NoPos {
+			return physical, errors.New("invalid secondary unit declaration")
+		}
+		secondary_unit.End = p.prevEnd
+		physical.SecondaryUnitDeclarations = append(physical.SecondaryUnitDeclarations, secondary_unit)
+	}
+
+	if p.expect(token.END) == token.NoPos || p.expect(token.UNITS) == token.NoPos {
+		return physical, errors.New("invalid physical type definition")
+	}
+	physical.PhysicalTypeSimpleName = p.parseClosingSimpleName(identifier)
+	physical.End = p.prevEnd
+	return physical, nil
+}
+
+func (p *Parser) parseArrayTypeDefinition() (ast.TypeDefinition, error) {
+	var type_definition ast.TypeDefinition
+	if p.trace {
+		defer un(trace(p, "ArrayTypeDefinition"))
+	}
+	pos := p.pos
+	if p.expect(token.ARRAY) == token.NoPos {
+		return type_definition, errors.New("invalid array type definition")
+	}
+
+	//An unbounded array has index subtype definitions, type_mark range <>, the
+	//ranges of a constrained array are parsed again as an index constraint
+	var index_subtype_definitions []ast.Name
+	c := p.checkpoint()
+	if p.expect(token.LPAREN) == token.NoPos {
+		return type_definition, errors.New("invalid array type definition")
+	}
+	for p.tok == token.IDENT {
+		type_mark, error := p.parseTypeMark()
+		if error != nil || p.tok != token.RANGE || p.tok2 != token.BOX {
+			index_subtype_definitions = nil
+			break
+		}
+		p.next()
+		p.next()
+		index_subtype_definitions = append(index_subtype_definitions, type_mark)
+		if p.tok != token.COMMA {
+			break
+		}
+		p.next()
+	}
+
+	if index_subtype_definitions != nil {
+		if p.expect(token.RPAREN) == token.NoPos {
+			return type_definition, errors.New("invalid array type definition")
+		}
+	} else {
+		p.restore(c)
+	}
+
+	var index_constraint ast.IndexConstraint
+	if index_subtype_definitions == nil {
+		constraint, error := p.parseIndexConstraint()
+		if error != nil {
+			return type_definition, errors.New("invalid index constraint")
+		}
+		index_constraint = constraint
+	}
+
+	if p.expect(token.OF) == token.NoPos {
+		return type_definition, errors.New("invalid array type definition")
+	}
+	element_subtype_indication, error := p.parseSubtypeIndication()
+	if error != nil {
+		return type_definition, errors.New("invalid element subtype indication")
+	}
+
+	node := ast.Node{Pos: pos, End: p.prevEnd}
+	if index_subtype_definitions != nil {
+		return ast.UnboundedArrayDefinition{IndexSubtypeDefinitions: index_subtype_definitions, ElementSubtypeIndication: element_subtype_indication, Node: node}, nil
+	}
+	return ast.ConstrainedArrayDefinition{IndexConstraint: index_constraint, ElementSubtypeIndication: element_subtype_indication, Node: node}, nil
+}
+
+func (p *Parser) parseRecordTypeDefinition(identifier ast.Identifier) (ast.RecordTypeDefinition, error) {
+	record := ast.RecordTypeDefinition{Node: ast.Node{Pos: p.pos}}
+	if p.trace {
+		defer un(trace(p, "RecordTypeDefinition"))
+	}
+	if p.expect(token.RECORD) == token.NoPos {
+		return record, errors.New("invalid record type definition")
+	}
+
+	for p.tok == token.IDENT {
+		element_declaration := ast.ElementDeclaration{Node: ast.Node{Pos: p.pos}}
+		identifier_list, error := p.parseIdentifierList()
+		if error != nil {
+			return record, errors.New("invalid identifier list")
+		}
+		element_declaration.IdentifierList = identifier_list
+		if p.expect(token.COLON) == token.NoPos {
+			return record, errors.New("invalid element declaration")
+		}
+		subtype_indication, error := p.parseSubtypeIndication()
+		if error != nil {
+			return record, errors.New("invalid subtype indication")
+		}
+		element_declaration.SubtypeIndication = subtype_indication
+		if p.expect(token.SEMICOLON) == token.NoPos {
+			return record, errors.New("invalid element declaration")
+		}
+		element_declaration.End = p.prevEnd
+		record.ElementDeclarations = append(record.ElementDeclarations, element_declaration)
+	}
+
+	if p.expect(token.END) == token.NoPos || p.expect(token.RECORD) == token.NoPos {
+		return record, errors.New("invalid record type definition")
+	}
+	record.RecordTypeSimpleName = p.parseClosingSimpleName(identifier)
+	record.End = p.prevEnd
+	return record, nil
+}
+
+// parseProtectedTypeDefinition parses a protected type declaration or, after PROTECTED BODY, its body.
+func (p *Parser) parseProtectedTypeDefinition(identifier ast.Identifier) (ast.TypeDefinition, error) {
+	var type_definition ast.TypeDefinition
+	if p.trace {
+		defer un(trace(p, "ProtectedTypeDefinition"))
+	}
+	pos := p.pos
+	p.checkStandard(pos, token.VHDL2002, "protected type")
+	if p.expect(token.PROTECTED) == token.NoPos {
+		return type_definition, errors.New("invalid protected type definition")
+	}
+	body := p.tok == token.BODY
+	if body {
+		p.next()
+	}
+
+	declarative_items, error := p.parseDeclarativePart()
+	if error != nil {
+		return type_definition, errors.New("invalid protected type declarative part")
+	}
+
+	if p.expect(token.END) == token.NoPos || p.expect(token.PROTECTED) == token.NoPos {
+		return type_definition, errors.New("invalid protected type definition")
+	}
+	if body && p.expect(token.BODY) == token.NoPos {
+		return type_definition, errors.New("invalid protected type body")
+	}
+	simple_name := p.parseClosingSimpleName(identifier)
+
+	node := ast.Node{Pos: pos, End: p.prevEnd}
+	if body {
+		return ast.ProtectedTypeBody{ProtectedTypeBodyDeclarativeItems: declarative_items, ProtectedTypeSimpleName: simple_name, Node: node}, nil
+	}
+	return ast.ProtectedTypeDeclaration{ProtectedTypeDeclarativeItems: declarative_items, ProtectedTypeSimpleName: simple_name, Node: node}, nil
+}
+
+// parseClosingSimpleName parses the optional simple name that closes the declaration of
+// identifier, it reports an error if the names differ.
+func (p *Parser) parseClosingSimpleName(identifier ast.Identifier) *ast.SimpleName {
+	if p.tok != token.IDENT {
+		return nil
+	}
+	simple_name := &ast.SimpleName{Identifier: p.identifier()}
+	if !token.EqualIdent(identifier.Identifier, p.lit) {
+		p.errorExpected(p.pos, "expected %s, found %s", identifier.Identifier, p.lit)
+	}
+	p.next()
+	return simple_name
+}
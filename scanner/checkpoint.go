@@ -0,0 +1,59 @@
+package scanner
+
+import "vhdl/token"
+
+// Checkpoint is the state of a Scanner at some point of the source, Restore returns
+// the scanner to it so a parser can backtrack.
+type Checkpoint struct {
+	offset     int // file offsets
+	rdOffset   int
+	lineOffset int
+	ch         rune
+	prev       token.Token
+	protect    bool
+	end        int
+	leading    string
+	trailing   string
+	errorCount int
+	lines      int // lines of the file known at the checkpoint
+}
+
+// Checkpoint returns the current state of the scanner.
+func (s *Scanner) Checkpoint() Checkpoint {
+	return Checkpoint{
+		offset:     s.srcBase + s.offset,
+		rdOffset:   s.srcBase + s.rdOffset,
+		lineOffset: s.srcBase + s.lineOffset,
+		ch:         s.ch,
+		prev:       s.prev,
+		protect:    s.protect,
+		end:        s.end,
+		leading:    s.leading,
+		trailing:   s.trailing,
+		errorCount: s.ErrorCount,
+		lines:      s.file.LineCount(),
+	}
+}
+
+// Restore returns the scanner to the state of a checkpoint taken with the same source,
+// the next call to Scan returns the token that followed the checkpoint. The lines added
+// to the file and the errors counted since the checkpoint are dropped, they are found
+// again while scanning. A streaming scanner drops the source of the tokens already
+// scanned from its buffer, restoring a checkpoint of a dropped token panics.
+func (s *Scanner) Restore(c Checkpoint) {
+	if c.offset < s.srcBase {
+		panic("scanner checkpoint before the buffered source")
+	}
+	s.offset = c.offset - s.srcBase
+	s.rdOffset = c.rdOffset - s.srcBase
+	s.lineOffset = c.lineOffset - s.srcBase
+	s.ch = c.ch
+	s.prev = c.prev
+	s.protect = c.protect
+	s.end = c.end
+	s.leading, s.trailing = c.leading, c.trailing
+	s.ErrorCount = c.errorCount
+	if lines := s.file.Lines(); len(lines) > c.lines {
+		s.file.SetLines(lines[:c.lines])
+	}
+}
@@ -12,8 +12,13 @@ type Error struct {
 	Msg string
 }
 
+// Error formats the error as "file:line:col: message", the parts of the
+// position that are unknown are left out.
 func (e Error) Error() string {
-	return ""
+	if e.Pos.Filename != "" || e.Pos.IsValid() {
+		return e.Pos.String() + ": " + e.Msg
+	}
+	return e.Msg
 }
 
 type ErrorHandler func(pos token.Position, msg string)
@@ -1,9 +1,34 @@
 package scanner
 
+import "vhdl/token"
+
 //A mode value is a set of flags (or 0). They control scanner behavior.
 type Mode uint
 
 const (
 	ScanComments Mode = 1 << iota // return comments as COMMENT tokens
+	Latin1                        // source is ISO/IEC 8859-1
+	UTF8                          // source is UTF-8, without Latin1 or UTF8 the encoding is detected
+	Trivia                        // attach whitespace and comments to the tokens, see Scanner.Trivia
+	LineDirectives                // remap the positions that follow a --line filename:line[:column] comment
+	KeepContent                   // keep the source on the file for token.File.Text, see Scanner.Init
+
+)
 
+// The language standard is one of the following values, it decides which words are
+// reserved and which delimiters exist. Without one the scanner follows VHDL-2019.
+const (
+	VHDL2019 Mode = Mode(token.VHDL2019) << stdShift
+	VHDL2008 Mode = Mode(token.VHDL2008) << stdShift
+	VHDL2002 Mode = Mode(token.VHDL2002) << stdShift
+	VHDL93   Mode = Mode(token.VHDL93) << stdShift
+	VHDL87   Mode = Mode(token.VHDL87) << stdShift
+
+	stdShift      = 8
+	stdMask  Mode = 0xF << stdShift
 )
+
+// Standard returns the language standard selected by the mode.
+func (m Mode) Standard() token.Standard {
+	return token.Standard(m & stdMask >> stdShift)
+}
@@ -0,0 +1,337 @@
+package scanner
+
+import (
+	"fmt"
+	"vhdl/literal"
+	"vhdl/token"
+)
+
+// Preprocessor evaluates the VHDL-2019 conditional analysis tool directives (LRM 24.2)
+//
+//	`if VHDL_VERSION >= "2019" then ... `elsif ... then ... `else ... `end [if]
+//	`warning "message"
+//	`error "message"
+//
+// on top of a Scanner. Tokens inside inactive regions are dropped, the tokens returned
+// by Scan keep the positions they have in the original file. A `protect directive is
+// returned as a single PROTECT token, any other tool directive is returned untouched as a
+// BACKTICK token followed by its tokens.
+type Preprocessor struct {
+	scanner     Scanner
+	file        *token.File
+	err         ErrorHandler
+	identifiers map[string]string // conditional analysis identifiers, normalized
+	conds       []condition       // open `if directives, innermost last
+	errorCount  int               // number of errors reported
+
+	// one token look-ahead
+	pos    token.Pos
+	tok    token.Token
+	lit    string
+	peeked bool
+
+	Warn ErrorHandler // called for `warning directives; or nil
+}
+
+type condition struct {
+	pos    token.Pos // position of the `if
+	active bool      // tokens of the current branch are kept
+	taken  bool      // one of the branches was already selected
+	inElse bool      // the `else branch was found
+}
+
+// Init prepares the preprocessor to scan src, the arguments are the ones of Scanner.Init.
+// identifiers holds the values of the conditional analysis identifiers such as
+// VHDL_VERSION, TOOL_TYPE, TOOL_VENDOR, TOOL_NAME, TOOL_EDITION and TOOL_VERSION.
+func (p *Preprocessor) Init(file *token.File, src []byte, err ErrorHandler, mode Mode, identifiers map[string]string) {
+	p.file = file
+	p.err = err
+	p.identifiers = make(map[string]string, len(identifiers))
+	for name, value := range identifiers {
+		p.identifiers[token.NormalizeIdent(name)] = value
+	}
+	p.conds = p.conds[:0]
+	p.peeked = false
+	p.errorCount = 0
+	//Errors of inactive regions are not reported, that text is not analyzed
+	p.scanner.Init(file, src, func(pos token.Position, msg string) {
+		if !p.active() {
+			return
+		}
+		if p.err != nil {
+			p.err(pos, msg)
+		}
+		p.errorCount++
+	}, mode)
+}
+
+// ErrorCount returns the number of errors reported, errors of inactive regions are not
+// counted.
+func (p *Preprocessor) ErrorCount() int {
+	return p.errorCount
+}
+
+func (p *Preprocessor) error(pos token.Pos, msg string) {
+	if p.err != nil {
+		p.err(p.file.Position(pos), msg)
+	}
+	p.errorCount++
+}
+
+func (p *Preprocessor) errorf(pos token.Pos, format string, args ...any) {
+	p.error(pos, fmt.Sprintf(format, args...))
+}
+
+func (p *Preprocessor) active() bool {
+	return len(p.conds) == 0 || p.conds[len(p.conds)-1].active
+}
+
+func (p *Preprocessor) next() (token.Pos, token.Token, string) {
+	if p.peeked {
+		p.peeked = false
+		return p.pos, p.tok, p.lit
+	}
+	return p.scanner.Scan()
+}
+
+func (p *Preprocessor) peek() token.Token {
+	if !p.peeked {
+		p.pos, p.tok, p.lit = p.scanner.Scan()
+		p.peeked = true
+	}
+	return p.tok
+}
+
+// Scan returns the next token of an active region, see Scanner.Scan.
+func (p *Preprocessor) Scan() (pos token.Pos, tok token.Token, lit string) {
+	for {
+		pos, tok, lit = p.next()
+		switch tok {
+		case token.BACKTICK:
+			if p.directive(pos) {
+				continue
+			}
+		case token.EOF:
+			for len(p.conds) > 0 {
+				p.error(p.conds[len(p.conds)-1].pos, "`if directive without `end")
+				p.conds = p.conds[:len(p.conds)-1]
+			}
+			return
+		}
+		if p.active() {
+			return
+		}
+	}
+}
+
+// directive processes a conditional analysis directive after the '`' at pos,
+// it reports false if the directive is for some other tool.
+func (p *Preprocessor) directive(pos token.Pos) bool {
+	switch p.peek() {
+	case token.IF:
+		p.next()
+		//A nested `if of an inactive region is inactive whatever its condition, which is
+		//not evaluated: it may use identifiers that only another tool defines
+		parent := p.active()
+		value := false
+		if parent {
+			value = p.condition()
+		} else {
+			p.skipCondition()
+		}
+		p.conds = append(p.conds, condition{pos: pos, active: parent && value, taken: value})
+	case token.ELSIF:
+		p.next()
+		value := false
+		if p.parentActive() {
+			value = p.condition()
+		} else {
+			p.skipCondition()
+		}
+		if c := p.current(pos, "`elsif"); c != nil {
+			if c.inElse {
+				p.error(pos, "`elsif directive after `else")
+			}
+			c.active = p.parentActive() && !c.taken && value
+			c.taken = c.taken || value
+		}
+	case token.ELSE:
+		p.next()
+		if c := p.current(pos, "`else"); c != nil {
+			if c.inElse {
+				p.error(pos, "duplicate `else directive")
+			}
+			c.inElse = true
+			c.active = p.parentActive() && !c.taken
+			c.taken = true
+		}
+	case token.END:
+		endPos, _, _ := p.next()
+		//`end may be followed by if on the same line
+		if p.peek() == token.IF && p.file.Line(p.pos) == p.file.Line(endPos) {
+			p.next()
+		}
+		if p.current(pos, "`end") != nil {
+			p.conds = p.conds[:len(p.conds)-1]
+		}
+	case token.IDENT:
+		switch token.NormalizeIdent(p.lit) {
+		case "warning", "error":
+			_, _, name := p.next()
+			msgPos, tok, lit := p.next()
+			if tok != token.STRING {
+				p.errorf(msgPos, "expected string literal after `%s, found %s", name, tok)
+				return true
+			}
+			if !p.active() {
+				return true
+			}
+			if msg := unquote(lit); token.NormalizeIdent(name) == "error" {
+				p.error(pos, msg)
+			} else if p.Warn != nil {
+				p.Warn(p.file.Position(pos), msg)
+			}
+		default:
+			return false
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// current returns the innermost open `if for a directive, or nil after reporting an error.
+func (p *Preprocessor) current(pos token.Pos, directive string) *condition {
+	if len(p.conds) == 0 {
+		p.errorf(pos, "%s directive without `if", directive)
+		return nil
+	}
+	return &p.conds[len(p.conds)-1]
+}
+
+func (p *Preprocessor) parentActive() bool {
+	return len(p.conds) < 2 || p.conds[len(p.conds)-2].active
+}
+
+// condition parses "conditional_analysis_expression then" and returns its value.
+func (p *Preprocessor) condition() bool {
+	value, ok := p.expression()
+	if ok {
+		if p.peek() == token.THEN {
+			p.next()
+			return value
+		}
+		p.errorf(p.pos, "expected THEN, found %s", p.tok)
+	}
+	p.skipCondition()
+	return false
+}
+
+// skipCondition skips the rest of a condition up to and including its THEN.
+func (p *Preprocessor) skipCondition() {
+	for tok := p.peek(); tok != token.THEN && tok != token.EOF; tok = p.peek() {
+		p.next()
+	}
+	if p.tok == token.THEN {
+		p.next()
+	}
+}
+
+// conditional_analysis_expression := relation { and relation } | relation { or relation }
+// | relation { xor relation } | relation { xnor relation }
+func (p *Preprocessor) expression() (bool, bool) {
+	value, ok := p.relation()
+	if !ok {
+		return false, false
+	}
+	op := token.ILLEGAL
+	for tok := p.peek(); tok == token.AND || tok == token.OR || tok == token.XOR || tok == token.XNOR; tok = p.peek() {
+		pos, _, _ := p.next()
+		if op != token.ILLEGAL && op != tok {
+			//Like in any other expression the logical operators can not be mixed without parentheses
+			p.errorf(pos, "%s can not follow %s without parentheses", tok, op)
+			return false, false
+		}
+		op = tok
+		right, ok := p.relation()
+		if !ok {
+			return false, false
+		}
+		switch op {
+		case token.AND:
+			value = value && right
+		case token.OR:
+			value = value || right
+		case token.XOR:
+			value = value != right
+		case token.XNOR:
+			value = value == right
+		}
+	}
+	return value, true
+}
+
+// conditional_analysis_relation := ( expression ) | not ( expression )
+// | conditional_analysis_identifier relational_operator string_literal
+func (p *Preprocessor) relation() (bool, bool) {
+	pos, tok, lit := p.next()
+	switch tok {
+	case token.NOT:
+		if pos, tok, _ := p.next(); tok != token.LPAREN {
+			p.errorf(pos, "expected ( after NOT, found %s", tok)
+			return false, false
+		}
+		value, ok := p.parenthesized()
+		return !value, ok
+	case token.LPAREN:
+		return p.parenthesized()
+	case token.IDENT:
+		value, defined := p.identifiers[token.NormalizeIdent(lit)]
+		if !defined {
+			p.errorf(pos, "undefined conditional analysis identifier %s", lit)
+		}
+		opPos, op, _ := p.next()
+		strPos, str, strLit := p.next()
+		if str != token.STRING {
+			p.errorf(strPos, "expected string literal, found %s", str)
+			return false, false
+		}
+		s := unquote(strLit)
+		switch op {
+		case token.EQL:
+			return value == s, defined
+		case token.NEQ:
+			return value != s, defined
+		case token.LTH:
+			return value < s, defined
+		case token.LEQ_SA:
+			return value <= s, defined
+		case token.GTH:
+			return value > s, defined
+		case token.GEQ:
+			return value >= s, defined
+		}
+		p.errorf(opPos, "expected relational operator, found %s", op)
+	default:
+		p.errorf(pos, "expected conditional analysis identifier, found %s", tok)
+	}
+	return false, false
+}
+
+func (p *Preprocessor) parenthesized() (bool, bool) {
+	value, ok := p.expression()
+	if !ok {
+		return false, false
+	}
+	if pos, tok, _ := p.next(); tok != token.RPAREN {
+		p.errorf(pos, "expected ), found %s", tok)
+		return false, false
+	}
+	return value, true
+}
+
+// unquote returns the value of a string literal, the scanner already reported malformed ones.
+func unquote(lit string) string {
+	value, _ := literal.Unquote(lit)
+	return value
+}
@@ -0,0 +1,127 @@
+package scanner
+
+import (
+	"strings"
+	"testing"
+	"vhdl/token"
+)
+
+func preprocess(src string, identifiers map[string]string) (idents []string, lines []int, errs ErrorList, warnings ErrorList) {
+	var p Preprocessor
+	fset := token.NewFileSet()
+	file := fset.AddFile("test.vhd", fset.Base(), len(src))
+	p.Init(file, []byte(src), errs.Add, 0, identifiers)
+	p.Warn = warnings.Add
+	for {
+		pos, tok, lit := p.Scan()
+		if tok == token.EOF {
+			break
+		}
+		idents = append(idents, lit)
+		lines = append(lines, fset.Position(pos).Line)
+	}
+	return
+}
+
+func TestPreprocessorBranches(t *testing.T) {
+	src := "a\n" +
+		"`if VHDL_VERSION >= \"2008\" and tool_name = \"sim\" then\n" +
+		"b\n" +
+		"`if TOOL_TYPE = \"SYNTHESIS\" then\n" +
+		"c\n" +
+		"`else\n" +
+		"d\n" +
+		"`end if\n" +
+		"`elsif VHDL_VERSION = \"1993\" then\n" +
+		"e\n" +
+		"`else\n" +
+		"f\n" +
+		"`end\n" +
+		"g\n"
+	tests := []struct {
+		version, name, tool string
+		want                string
+		lines               []int
+	}{
+		{"2019", "sim", "SIMULATION", "a b d g", []int{1, 3, 7, 14}},
+		{"2019", "sim", "SYNTHESIS", "a b c g", []int{1, 3, 5, 14}},
+		{"1993", "sim", "SYNTHESIS", "a e g", []int{1, 10, 14}},
+		{"2008", "other", "SYNTHESIS", "a f g", []int{1, 12, 14}},
+	}
+	for _, test := range tests {
+		identifiers := map[string]string{"VHDL_VERSION": test.version, "TOOL_NAME": test.name, "TOOL_TYPE": test.tool}
+		idents, lines, errs, _ := preprocess(src, identifiers)
+		if len(errs) != 0 {
+			t.Errorf("%v: unexpected errors: %v", test, errs)
+		}
+		if got := strings.Join(idents, " "); got != test.want {
+			t.Errorf("%v: got %q, want %q", test, got, test.want)
+		}
+		for i := range lines {
+			if i < len(test.lines) && lines[i] != test.lines[i] {
+				t.Errorf("%v: token %s on line %d, want %d", test, idents[i], lines[i], test.lines[i])
+			}
+		}
+	}
+}
+
+func TestPreprocessorInactiveConditions(t *testing.T) {
+	//Identifiers of another tool are only used in its own region
+	src := "`if TOOL_NAME = \"x\" then\n" +
+		"`if VENDOR_ONLY_ID = \"1\" then\na\n`elsif VENDOR_OTHER_ID = \"2\" then\nb\n`end\n" +
+		"`else\nc\n`end\n"
+	idents, _, errs, _ := preprocess(src, map[string]string{"TOOL_NAME": "y"})
+	if len(errs) != 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	if got := strings.Join(idents, " "); got != "c" {
+		t.Errorf("got %q, want %q", got, "c")
+	}
+}
+
+func TestPreprocessorDirectives(t *testing.T) {
+	src := "`if not (VHDL_VERSION < \"2019\") then\n" +
+		"`warning \"new\"\n" +
+		"`else\n" +
+		"`error \"old\"\n" +
+		"`end\n" +
+		"`protect begin\n"
+	_, _, errs, warnings := preprocess(src, map[string]string{"VHDL_VERSION": "2019"})
+	if len(errs) != 0 || len(warnings) != 1 || warnings[0].Msg != "new" {
+		t.Errorf("got errors %v and warnings %v, want warning new", errs, warnings)
+	}
+	idents, _, errs, _ := preprocess(src, map[string]string{"VHDL_VERSION": "2008"})
+	if len(errs) != 1 || errs[0].Msg != "old" {
+		t.Errorf("got errors %v, want error old", errs)
+	}
+	//Other tool directives are passed through
+	if got := strings.Join(idents, " "); got != "`protect begin" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestPreprocessorErrors(t *testing.T) {
+	tests := []string{
+		"`if X = \"1\" and Y = \"1\" or Z = \"1\" then\n`end\n",
+		"`if UNDEFINED = \"1\" then\n`end\n",
+		"`if X = \"1\" then\n",
+		"`else\n",
+		"`if X then\n`end\n",
+	}
+	for _, src := range tests {
+		if _, _, errs, _ := preprocess(src, map[string]string{"X": "1", "Y": "1", "Z": "1"}); len(errs) == 0 {
+			t.Errorf("%q: expected an error", src)
+		}
+	}
+	//Scanning errors of inactive regions are not reported
+	src := "`if X = \"0\" then\n#$\n`end\n"
+	var p Preprocessor
+	var errs ErrorList
+	file := token.NewFileSet().AddFile("test.vhd", -1, len(src))
+	p.Init(file, []byte(src), errs.Add, 0, map[string]string{"X": "1"})
+	for _, tok, _ := p.Scan(); tok != token.EOF; _, tok, _ = p.Scan() {
+	}
+	if len(errs) != 0 || p.ErrorCount() != 0 {
+		t.Errorf("got errors %v and error count %d, want none", errs, p.ErrorCount())
+	}
+}
@@ -3,28 +3,47 @@ package scanner
 //TODO make scanner more readle
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"unicode"
+	"unicode/utf8"
+	"vhdl/literal"
 	"vhdl/token"
 )
 
 type Scanner struct {
 	//Similar structure to go scnnner
 	// pub	// immutable state
-	file *token.File  // source file handle
-	dir  string       // directory portion of file.Name()
-	src  []byte       // source
-	err  ErrorHandler // error reporting; or nil
-	mode Mode         // scanning mode
+	file   *token.File  // source file handle
+	dir    string       // directory portion of file.Name()
+	src    []byte       // source, or the buffered part of a streamed source
+	err    ErrorHandler // error reporting; or nil
+	mode   Mode         // scanning mode
+	latin1 bool         // source is ISO/IEC 8859-1, otherwise UTF-8
+	std    token.Standard
+
+	// streaming state
+	reader  io.Reader // source of a streaming scanner, or nil once it is exhausted
+	srcBase int       // file offset of src[0], the scanning offsets are relative to src
+	empty   int       // consecutive reads that returned nothing
 
 	// scanning state
-	ch         rune // current character
-	offset     int  // character offset
-	rdOffset   int  // reading offset (position after current character)
-	lineOffset int  // current line offset
+	ch         rune        // current character
+	offset     int         // character offset
+	rdOffset   int         // reading offset (position after current character)
+	lineOffset int         // current line offset
+	prev       token.Token // last token returned by Scan, comments excluded
+	protect    bool        // the last token opens a key, data or digest block
+
+	end int // file offset of the end of the last token
+
+	// trivia of the last token, only in Trivia mode
+	leading  string
+	trailing string
 	//public state - ok to modify
 	ErrorCount int // number of errors encountered
 	// contains filtered or unexported fields
@@ -35,22 +54,59 @@ const (
 	eof = -1     // end of file
 )
 
+var bomUTF8 = []byte{0xEF, 0xBB, 0xBF}
+
+// Init prepares the scanner to tokenize src, file must have the size of src. With the
+// KeepContent mode the source is kept on the file with token.File.SetContent, so the
+// file can return the text of positions; a streamed source is never kept.
 func (s *Scanner) Init(file *token.File, src []byte, err ErrorHandler, mode Mode) {
 	if file.Size() != len(src) {
 		panic(fmt.Sprintf("file size (%d) does not match src len (%d)", file.Size(), len(src)))
 	}
+	if mode&KeepContent != 0 {
+		file.SetContent(src)
+	}
+	s.src = src
+	s.reader = nil
+	s.init(file, err, mode)
+}
+
+// init resets the scanner state for the source in s.src and s.reader.
+func (s *Scanner) init(file *token.File, err ErrorHandler, mode Mode) {
 	s.file = file
 	s.dir, _ = filepath.Split(file.Name())
-	s.src = src
 	s.err = err
 	s.mode = mode
+	s.std = mode.Standard()
 
+	s.srcBase = 0
+	s.empty = 0
 	s.ch = ' '
 	s.offset = 0
 	s.rdOffset = 0
 	s.lineOffset = 0
+	s.prev = token.ILLEGAL
+	s.protect = false
+	s.end = 0
+	s.leading, s.trailing = "", ""
 	s.ErrorCount = 0
 
+	//The start of a streamed source decides its encoding
+	s.fill(streamChunk)
+	sample := s.src
+	if s.reader != nil {
+		sample = completeRunes(sample)
+	}
+	switch {
+	case mode&Latin1 != 0:
+		s.latin1 = true
+	case mode&UTF8 != 0:
+		s.latin1 = false
+	default:
+		//A byte order mark or a valid UTF-8 sequence selects UTF-8, otherwise VHDL default ISO/IEC 8859-1
+		s.latin1 = !bytes.HasPrefix(sample, bomUTF8) && !utf8.Valid(sample)
+	}
+
 	s.next()
 	if s.ch == bom {
 		s.next() // ignore BOM at file beginning
@@ -58,7 +114,7 @@ func (s *Scanner) Init(file *token.File, src []byte, err ErrorHandler, mode Mode
 }
 func (s *Scanner) error(offs int, msg string) {
 	if s.err != nil {
-		s.err(s.file.Position(s.file.Pos(offs)), msg)
+		s.err(s.file.Position(s.pos(offs)), msg)
 	}
 	s.ErrorCount++
 }
@@ -66,101 +122,213 @@ func (s *Scanner) error(offs int, msg string) {
 func (s *Scanner) errorf(offs int, format string, args ...any) {
 	s.error(offs, fmt.Sprintf(format, args...))
 }
+
+// pos returns the position of the offset offs of the source buffer.
+func (s *Scanner) pos(offs int) token.Pos {
+	return s.file.Pos(s.srcBase + offs)
+}
+
 func (s *Scanner) next() {
-	if s.rdOffset >= len(s.src) {
+	if !s.fill(utf8.UTFMax) && s.rdOffset >= len(s.src) {
 		s.offset = len(s.src)
 		if s.ch == '\n' {
 			s.lineOffset = s.offset
-			s.file.AddLine(s.lineOffset)
+			s.file.AddLine(s.srcBase + s.lineOffset)
 		}
 		s.ch = eof
 		return
 	}
+	s.offset = s.rdOffset
 	if s.ch == '\n' {
+		//The new line starts after the '\n'
 		s.lineOffset = s.offset
-		s.file.AddLine(s.offset)
+		s.file.AddLine(s.srcBase + s.offset)
 	}
-	s.ch = rune(s.src[s.rdOffset])
-
-	s.offset = s.rdOffset //Increase 1 byte
-	s.rdOffset += 1       //Increase 1 byte
-	if s.ch == 0 {
+	r, w := rune(s.src[s.rdOffset]), 1
+	switch {
+	case r == 0:
 		s.error(s.offset, "illegal character NUL")
+	case r >= utf8.RuneSelf && !s.latin1:
+		//Not ASCII, a ISO/IEC 8859-1 byte is already the character
+		r, w = utf8.DecodeRune(s.src[s.rdOffset:])
+		if r == utf8.RuneError && w == 1 {
+			s.error(s.offset, "illegal UTF-8 encoding")
+		} else if r == bom && s.srcBase+s.offset > 0 {
+			s.error(s.offset, "illegal byte order mark")
+		}
+		if w > 1 {
+			s.file.AddMultiByteChar(s.srcBase+s.offset, w)
+		}
 	}
-
+	s.rdOffset += w
+	s.ch = r
 }
 
 func (s *Scanner) Peek() rune {
-	if s.rdOffset < len(s.src) {
-		return rune(s.src[s.rdOffset])
+	if s.fill(utf8.UTFMax) || s.rdOffset < len(s.src) {
+		r := rune(s.src[s.rdOffset])
+		if r >= utf8.RuneSelf && !s.latin1 {
+			r, _ = utf8.DecodeRune(s.src[s.rdOffset:])
+		}
+		return r
 	}
 	return 0
 }
 
-func (s *Scanner) scanMultipleLineComment() (string, bool) {
-	offs := s.offset - 1
-	valid := false //If set means a valid comment was found
-	//On VHDL we have /**/ commants and -- comments
-	if s.ch == '*' {
-		//Consumed the '*'
-		for s.ch >= 0 { //Consume everything
-			s.next()
-			if s.ch == '*' && s.Peek() == '/' {
-				s.next() //Consume '*'
-				s.next() //Consume '/'
-				valid = true
-				break
-			}
+// text returns the source in [offs, end) as a UTF-8 string.
+func (s *Scanner) text(offs, end int) string {
+	src := s.src[offs:end]
+	if !s.latin1 {
+		return string(src)
+	}
+	//ISO/IEC 8859-1 maps each byte to the code point with the same value
+	var b strings.Builder
+	b.Grow(len(src))
+	for _, c := range src {
+		b.WriteRune(rune(c))
+	}
+	return b.String()
+}
 
+func (s *Scanner) scanMultipleLineComment() string {
+	offs := s.offset - 1
+	//Consumed the '/', s.ch is the '*'
+	for s.ch >= 0 { //Consume everything
+		s.next()
+		if s.ch == '*' && s.Peek() == '/' {
+			s.next() //Consume '*'
+			s.next() //Consume '/'
+			return s.text(offs, s.offset)
 		}
-
 	}
-	return string(s.src[offs:s.offset]), valid
+	s.error(offs, "comment not terminated")
+	return s.text(offs, s.offset)
 }
 
-func (s *Scanner) scanSingleLineComment() (string, bool) {
+func (s *Scanner) scanSingleLineComment() string {
 	offs := s.offset - 1
-	valid := false //If set means a valid comment was found
-	//On VHDL we have /**/ commants and -- comments
-	if s.ch == '-' {
-		s.next()                        //consume '-'
-		for s.ch != '\n' && s.ch >= 0 { //Consume everything valid until newline
-			s.next() //Consume everything
-		}
+	//Consumed the first '-', the comment ends at the end of the line or of the file
+	for s.ch != '\n' && s.ch >= 0 {
+		s.next()
+	}
+	comment := s.text(offs, s.offset)
+	if s.mode&LineDirectives != 0 && offs == s.lineOffset && strings.HasPrefix(comment, "--line ") {
+		s.updateLineInfo(comment)
+	}
+	return comment
+}
 
-		if s.ch == '\n' {
-			valid = true //If the current rune is newlinw the comment is valid
+// updateLineInfo handles a line directive, a comment "--line filename:line[:column]"
+// at the start of a line. The line that follows it is at line and column of filename
+// for the positions adjusted by token.File.PositionFor, without a column the columns
+// are unknown. A comment that is not a valid directive is an ordinary comment.
+func (s *Scanner) updateLineInfo(comment string) {
+	text := strings.TrimRight(comment[len("--line "):], " \t\r")
+	i := strings.LastIndexByte(text, ':')
+	if i < 0 {
+		//Not a line directive
+		return
+	}
+	n, err := strconv.Atoi(text[i+1:])
+	if err != nil || n <= 0 {
+		return
+	}
+	filename, line, column := text[:i], n, 0
+	if j := strings.LastIndexByte(filename, ':'); j >= 0 {
+		if m, err := strconv.Atoi(filename[j+1:]); err == nil {
+			if m <= 0 {
+				return
+			}
+			filename, line, column = filename[:j], m, n
 		}
 	}
-	return string(s.src[offs:s.offset]), valid
+	//The directive applies to the next line, it must be part of the file
+	if s.ch == '\n' && s.fill(1) {
+		s.file.AddLineColumnInfo(s.srcBase+s.rdOffset, filename, line, column)
+	}
+}
+
+// commentToken returns PRAGMA for the metacomments of synthesis tools, otherwise COMMENT.
+func commentToken(comment string) token.Token {
+	if _, ok := literal.ParsePragma(comment); ok {
+		return token.PRAGMA
+	}
+	return token.COMMENT
 }
 
 func (s *Scanner) scanIdentifier() string {
 	offs := s.offset
-	valid := false
-	for rdOffset, b := range s.src[s.rdOffset:] {
-		//VHDL uses ISO/IEC 8859-1 which kinda extends ASCII for now only ascii is accepted
-		if 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' || b == '_' || '0' <= b && b <= '9' {
-			// Avoid assigning a rune for the common case of an ascii character.
-			continue
+	for s.fill(1) {
+		for rdOffset, b := range s.src[s.rdOffset:] {
+			if 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' || b == '_' || '0' <= b && b <= '9' {
+				// Avoid assigning a rune for the common case of an ascii character.
+				continue
+			}
+			//Update counters
+			s.rdOffset += rdOffset
+			if 0 < b && b < utf8.RuneSelf {
+				//ASCII character that ends the identifier, no need to call s.next()
+				s.ch = rune(b)
+				s.offset = s.rdOffset
+				s.rdOffset++
+				return s.text(offs, s.offset)
+			}
+			//Letters of ISO/IEC 8859-1 outside ASCII are decoded by s.next(), the
+			//previous character is part of the identifier so s.next() can resume from here
+			s.next()
+			for isLetter(s.ch) || isDecimal(s.ch) || s.ch == '_' {
+				s.next()
+			}
+			return s.text(offs, s.offset)
 		}
-		//Update counters
-		s.rdOffset += rdOffset
-		if 0 < b {
-			s.ch = rune(b)
-			s.offset = s.rdOffset
-			s.rdOffset++
-			valid = true
+		//The buffer ends inside the identifier, read more of a streamed source
+		s.rdOffset = len(s.src)
+	}
+	s.offset = len(s.src)
+	s.rdOffset = len(s.src)
+	s.ch = eof
+
+	return s.text(offs, s.offset)
+}
+
+func (s *Scanner) scanExtendedIdentifier() string {
+	offs := s.offset - 1 //Already consumed '\'
+	reported := false
+	//extended_identifier := \ graphic_character { graphic_character } \
+	//A backslash inside the identifier is written as two adjacent backslashes
+	for {
+		ch := s.ch
+		if ch == '\n' || ch == '\r' || ch < 0 {
+			s.error(offs, "extended identifier not terminated")
+			break
+		}
+		if !isGraphic(ch) && !reported {
+			s.errorf(s.offset, "invalid character %#U in extended identifier", ch)
+			reported = true
+		}
+		s.next()
+		if ch == '\\' {
+			if s.ch != '\\' {
+				break //Closing backslash
+			}
+			s.next() //Consume the doubled backslash
 		}
-		break
 	}
-	if !valid {
-		s.offset = len(s.src)
-		s.rdOffset = len(s.src)
-		s.ch = eof
+	lit := s.text(offs, s.offset)
+	if lit == `\\` {
+		s.error(offs, "empty extended identifier")
 	}
+	return lit
+}
 
-	return string(s.src[offs:s.offset])
+// isGraphic reports whether ch is a graphic_character of ISO/IEC 8859-1 (LRM 15.2).
+func isGraphic(ch rune) bool {
+	return ' ' <= ch && ch <= '~' || 0xA0 <= ch
+}
+
+// isLetter reports whether ch is a letter of ISO/IEC 8859-1 (LRM 15.2), × and ÷ are not letters.
+func isLetter(ch rune) bool {
+	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || 0xC0 <= ch && ch <= 0xFF && ch != 0xD7 && ch != 0xF7
 }
 
 func lower(ch rune) rune     { return unicode.ToLower(ch) }
@@ -170,7 +338,6 @@ func isHex(ch rune) bool     { return ('0' <= ch && ch <= '9') || ('a' <= lower(
 func (s *Scanner) scanDigits(base int, invalid *int) (digsep int) {
 	var valid func(rune) bool
 	var separator int
-	max := rune('0' + base)
 
 	if base <= 10 {
 		valid = func(r rune) bool { return isDecimal(r) }
@@ -178,27 +345,41 @@ func (s *Scanner) scanDigits(base int, invalid *int) (digsep int) {
 		valid = isHex
 	}
 
+	underline := -1 // offset of a misplaced underline
 	for valid(s.ch) || s.ch == '_' {
 		separator = 1
 		if s.ch == '_' {
 			separator = 2
-		} else if (base <= 10 && s.ch > max) && *invalid < 0 {
+			//An underline must follow a digit
+			if (digsep&1 == 0 || s.src[s.offset-1] == '_') && underline < 0 {
+				underline = s.offset
+			}
+		} else if literal.DigitValue(s.ch) >= base && *invalid < 0 {
 			*invalid = s.offset // record invalid rune offset
 		}
 		digsep |= separator
 		s.next()
 	}
+	if underline < 0 && separator == 2 {
+		//and be followed by a digit
+		underline = s.offset - 1
+	}
+	if underline >= 0 {
+		s.error(underline, "underline must separate two digits")
+	}
 
 	return
 }
+
 func (s *Scanner) scanNumber() (token.Token, string) {
-	var err error
 	offs := s.offset
 	tok := token.INT
 	base := 10
 	invalid := -1
+	validBase := true
 	digsep := 0 // bit 0: digit present, bit 1: '_' present
 	based_delimiter_count := 0
+	point := false
 
 	//We have already consume a digit, it is the current s.ch
 	// We are searching for abstract literals := Based_lteral|Decimal_literal
@@ -209,12 +390,17 @@ func (s *Scanner) scanNumber() (token.Token, string) {
 	if s.ch == '#' {
 		tok = token.BASED
 		based_delimiter_count++
-		baseString := string(s.src[offs:s.offset])
-		if base, err = strconv.Atoi(baseString); err != nil {
-			s.error(s.lineOffset, "Invalid digit for the base")
+		baseString := strings.ReplaceAll(s.text(offs, s.offset), "_", "")
+		var err error
+		if base, err = strconv.Atoi(baseString); err != nil || base < 2 || base > 16 {
+			s.errorf(offs, "invalid base %s, the base must be between 2 and 16", baseString)
+			base = 16 //Keep scanning the based digits
+			validBase = false
 		}
 		s.next()
-		digsep |= s.scanDigits(base, &invalid)
+		if ds := s.scanDigits(base, &invalid); ds&1 == 0 {
+			s.error(s.offset, "missing digits in based literal")
+		}
 	}
 
 	if s.ch == '.' {
@@ -222,10 +408,10 @@ func (s *Scanner) scanNumber() (token.Token, string) {
 			//First . not #
 			tok = token.REAL
 		}
+		point = true
 		s.next()
-		digsep |= s.scanDigits(base, &invalid)
-		if digsep&1 == 0 {
-			s.error(s.offset, "Missing digits fractional part")
+		if ds := s.scanDigits(base, &invalid); ds&1 == 0 {
+			s.error(s.offset, "missing digits in fractional part")
 		}
 	}
 
@@ -235,54 +421,167 @@ func (s *Scanner) scanNumber() (token.Token, string) {
 	}
 
 	if lower(s.ch) == 'e' {
+		exp := s.offset
 		//We consume the 'E'
 		s.next()
 		//We consume the '-' or the '+' if they exists
+		negative := s.ch == '-'
 		if s.ch == '+' || s.ch == '-' {
 			s.next()
 		}
-		ds := s.scanDigits(10, nil)
+		ds := s.scanDigits(10, &invalid)
 		digsep |= ds
 		if ds&1 == 0 {
 			s.error(s.offset, "exponent has no digits")
 		}
+		if negative && !point {
+			s.error(exp, "negative exponent in integer literal")
+		}
+	}
+
+	if invalid >= 0 && validBase {
+		s.errorf(invalid, "invalid digit %q in base %d literal", s.src[invalid], base)
 	}
-	lit := string(s.src[offs:s.offset])
 	if tok == token.BASED && based_delimiter_count != 2 {
-		tok = token.ILLEGAL
-		lit = ""
+		s.error(s.offset, "based literal not terminated, expected '#'")
 	}
-	return tok, lit
+	return tok, s.text(offs, s.offset)
 
 }
 
 func (s *Scanner) scanRune() string {
 	offs := s.offset - 1 // Consumed "'"
 	//VHDL char is 'graphic_character'
-	if s.ch == '\n' {
-		s.error(s.offset, "Breaking space character")
+	if !isGraphic(s.ch) {
+		s.errorf(s.offset, "invalid character %#U in character literal", s.ch)
 	}
 	s.next() //Consume graphic_character
 	s.next() //Consume '
-	return string(s.src[offs:s.offset])
+	return s.text(offs, s.offset)
 }
 
 func (s *Scanner) scanString() string {
 	offs := s.offset - 1 //Already consumed "
 	for {
-		if s.ch == '\n' {
+		ch := s.ch
+		if ch == '\n' || ch == '\r' || ch < 0 {
 			s.error(offs, "string literal not terminated")
 			break
 		}
 		s.next()
-		if s.ch == '"' {
-			//Consume '"'
+		if ch == '"' {
+			//A doubled quotation mark stands for one quotation mark in the string
+			if s.ch != '"' {
+				break
+			}
 			s.next()
-			break
 		}
 	}
-	return string(s.src[offs:s.offset])
+	return s.text(offs, s.offset)
+}
+
+// isCharacterLiteral reports whether the apostrophe just consumed starts a character literal.
+// After a name an apostrophe is the attribute or qualified expression tick, so
+// character'('a') is a tick followed by the parenthesized character literal 'a'.
+func (s *Scanner) isCharacterLiteral() bool {
+	switch s.prev {
+	case token.IDENT, token.RPAREN, token.RSQPAREN, token.ALL:
+		return false
+	}
+	return s.ch >= 0 && s.fill(1) && s.src[s.rdOffset] == '\''
+}
+
+// scanTrivia consumes the separators and comments before the next token and returns
+// their text. Trailing trivia stops after the first line break, what follows is the
+// leading trivia of the next token.
+func (s *Scanner) scanTrivia(trailing bool) string {
+	offs := s.offset
+	for {
+		switch {
+		case s.ch == ' ' || s.ch == '\t' || s.ch == '\r':
+			s.next()
+		case s.ch == '\n':
+			s.next()
+			if trailing {
+				return s.text(offs, s.offset)
+			}
+		case s.ch == '-' && s.Peek() == '-':
+			s.next()
+			s.scanSingleLineComment()
+		case s.ch == '/' && s.Peek() == '*':
+			s.next()
+			s.scanMultipleLineComment()
+		default:
+			return s.text(offs, s.offset)
+		}
+	}
+}
+
+// Trivia returns the whitespace and comments around the last token returned by Scan
+// in Trivia mode. The leading trivia, the token literal and the trailing trivia of all
+// the tokens up to EOF reproduce the source; for an ISO/IEC 8859-1 source it is the
+// UTF-8 encoding of the source.
+func (s *Scanner) Trivia() (leading, trailing string) {
+	return s.leading, s.trailing
+}
+
+// End returns the position immediately after the last token returned by Scan. It is
+// taken from the source, the literal of a Latin-1 source is longer than its text.
+func (s *Scanner) End() token.Pos {
+	return s.file.Pos(s.end)
+}
+
+// isProtectDirective reports whether the '`' just consumed starts a protect tool directive (LRM 24.1).
+func (s *Scanner) isProtectDirective() bool {
+	return s.isProtectKeyword(s.offset)
+}
+
+// isProtectKeyword reports whether the source at offs starts with the protect keyword.
+func (s *Scanner) isProtectKeyword(offs int) bool {
+	const protect = "protect"
+	end := offs + len(protect)
+	if s.fill(end + 1 - s.rdOffset); end > len(s.src) || !strings.EqualFold(string(s.src[offs:end]), protect) {
+		return false
+	}
+	return end == len(s.src) || !isLetter(rune(s.src[end])) && !isDecimal(rune(s.src[end])) && s.src[end] != '_'
+}
+
+// scanProtectDirective consumes the rest of the line of a protect tool directive. The
+// directive is returned as a whole, its keyword and values are not analyzed.
+func (s *Scanner) scanProtectDirective() string {
+	offs := s.offset - 1 //Already consumed '`'
+	for s.ch != '\n' && s.ch >= 0 {
+		s.next()
+	}
+	lit := strings.TrimRight(s.text(offs, s.offset), " \t\r")
+	switch keyword := strings.Fields(lit); {
+	case len(keyword) > 1 && strings.HasSuffix(strings.ToLower(keyword[1]), "_block"):
+		//key_block, data_block and digest_block are followed by the encoded lines
+		s.protect = true
+	}
+	return lit
+}
 
+// scanProtectData consumes the encoded lines of a key, data or digest block, they end
+// before the next line starting with a protect directive. The encoding is expected to be
+// one of the text encodings: base64, uuencode or quoted-printable, a uuencode line may
+// itself start with '`'.
+func (s *Scanner) scanProtectData() string {
+	offs := s.offset
+	for {
+		for s.ch != '\n' && s.ch >= 0 {
+			s.next()
+		}
+		end := s.offset
+		i := s.rdOffset
+		for s.fill(i+1-s.rdOffset) && (s.src[i] == ' ' || s.src[i] == '\t' || s.src[i] == '\r' || s.src[i] == '\n') {
+			i++
+		}
+		if s.ch < 0 || i == len(s.src) || s.src[i] == '`' && s.isProtectKeyword(i+1) {
+			return strings.TrimRight(s.text(offs, end), "\r")
+		}
+		s.next() //Consume '\n'
+	}
 }
 
 func (s *Scanner) skipWhitespace() {
@@ -296,41 +595,72 @@ func isBaseSpecifierPrefix(ch rune) bool {
 	return lower(ch) == 'b' || lower(ch) == 'o' || lower(ch) == 'u' || lower(ch) == 'x' || lower(ch) == 's' || lower(ch) == 'd'
 }
 
+// scanBitStringPrefix consumes the base specifier that follows the length of a bit
+// string literal. It reports false and consumes nothing if no '"' follows the specifier,
+// the specifier itself is validated with the rest of the literal.
 func (s *Scanner) scanBitStringPrefix() bool {
-	is_valid_bit_string_prefix := true
-	has_sign := false
-
-	if lower(s.ch) == 'u' || lower(s.ch) == 's' {
-		//Consume 'u' or 's'
-		s.next()
-		has_sign = true
+	//B|O|X|D or U|S followed by B|O|X
+	for n := 1; n <= 2 && s.fill(n); n++ {
+		if !isBaseSpecifierPrefix(rune(s.src[s.offset+n-1])) {
+			return false
+		}
+		if s.src[s.offset+n] == '"' {
+			for ; n > 0; n-- {
+				s.next()
+			}
+			return true
+		}
 	}
+	return false
+}
 
-	if lower(s.ch) == 'd' && has_sign {
-		is_valid_bit_string_prefix = false
+// checkBitString reports a malformed bit string literal starting at offs.
+func (s *Scanner) checkBitString(offs int, lit string) {
+	b, err := literal.ParseBitString(lit)
+	if err == nil {
+		if !s.std.Includes(token.VHDL2008) {
+			if b.Length >= 0 {
+				s.errorf(offs, "bit string length requires %s or later (current standard is %s)", token.VHDL2008, s.std)
+			}
+			if len(b.Base) == 2 || b.Base == "D" {
+				s.errorf(offs, "base specifier %s requires %s or later (current standard is %s)", b.Base, token.VHDL2008, s.std)
+			}
+		}
+		_, err = b.Expand()
+	}
+	if e, ok := err.(*literal.Error); ok {
+		s.error(offs+e.Offset, e.Msg)
 	}
-
-	//Consume X. O, B or D
-	s.next()
-
-	return is_valid_bit_string_prefix
 }
 
 func (s *Scanner) Scan() (pos token.Pos, tok token.Token, lit string) {
+	s.discard()
 
-	s.skipWhitespace()
-	pos = s.file.Pos(s.offset)
+	if s.mode&Trivia != 0 {
+		s.leading = s.scanTrivia(false)
+	} else {
+		s.skipWhitespace()
+	}
+	offs := s.offset
+	pos = s.pos(offs)
+	protect := s.protect
+	s.protect = false
 
 	switch ch := s.ch; {
 
+	//The encoded lines of a protected envelope up to the next tool directive
+	case protect && ch != eof && (ch != '`' || !s.isProtectKeyword(s.rdOffset)):
+		tok = token.PROTECT_DATA
+		lit = s.scanProtectData()
+
 	//--------------------------------------Letter-------------------------------------------------------
 	//We detected a graphic_character it could be a identifier or a keyword or a bit strign
-	case unicode.IsLetter(ch):
+	case isLetter(ch):
 		lit = s.scanIdentifier()
 		//If the len is greater than 1, we have a keyword, identifier or bit_string so we are going to match the literal to the token to see
 		//if it is a keyword
 		if len(lit) > 1 {
-			tok = token.Lookup(lit)
+			tok = token.LookupStandard(lit, s.std)
 			switch tok {
 			case token.IDENT:
 				//Ugly but needed to capture bit_str with number length
@@ -367,15 +697,12 @@ func (s *Scanner) Scan() (pos token.Pos, tok token.Token, lit string) {
 		//Right now we have a abstract literal to check if it as string we need the following
 		// that the lit is a integer, not float or based. It is followed by: B|O|X|UB|UO|UX|SB|SO|SX|D
 		if tok == token.INT && isBaseSpecifierPrefix(s.ch) {
-			offs := s.offset
+			start := s.offset
 			if s.scanBitStringPrefix() {
-				s.next()
+				s.next() //Consume '"'
 				s.scanString()
 				tok = token.BIT_STR
-				lit = lit + string(s.src[offs:s.offset])
-			} else {
-				tok = token.ILLEGAL
-				lit = lit + string(s.src[offs:s.offset])
+				lit = lit + s.text(start, s.offset)
 			}
 		}
 
@@ -394,14 +721,18 @@ func (s *Scanner) Scan() (pos token.Pos, tok token.Token, lit string) {
 		case '"':
 			tok = token.STRING
 			lit = s.scanString()
+		case '\\':
+			tok = token.IDENT
+			if !s.std.Includes(token.VHDL93) {
+				s.errorf(offs, "extended identifier requires %s or later (current standard is %s)", token.VHDL93, s.std)
+			}
+			lit = s.scanExtendedIdentifier()
 		case '\'':
-			//TODO add only ' recognition
-			//peek must be ' only 1 char inside ' '
-			if s.Peek() != '\'' {
-				tok = token.APOS
-			} else {
+			if s.isCharacterLiteral() {
 				tok = token.CHAR
 				lit = s.scanRune()
+			} else {
+				tok = token.APOS
 			}
 		case ':':
 			tok = token.COLON
@@ -430,18 +761,51 @@ func (s *Scanner) Scan() (pos token.Pos, tok token.Token, lit string) {
 			tok = token.MINUS
 			if s.ch == '-' {
 				//Comment
-				comment, valid := s.scanSingleLineComment()
-				tok = token.COMMENT
-				if !valid {
-					tok = token.ILLEGAL
-				}
-				lit = comment
+				lit = s.scanSingleLineComment()
+				tok = commentToken(lit)
 			}
 
 		case '&':
 			tok = token.CONCAT
 		case '?':
-			tok = token.COND_CONV
+			//VHDL-2008 condition operator and matching relational operators
+			tok = token.QUEST
+			switch s.ch {
+			case '?':
+				tok = token.COND_CONV
+				s.next()
+			case '=':
+				tok = token.MEQ
+				s.next()
+			case '/':
+				if s.Peek() == '=' {
+					tok = token.MNEQ
+					s.next()
+					s.next()
+				}
+			case '<':
+				tok = token.MLTH
+				s.next()
+				if s.ch == '=' {
+					tok = token.MLEQ
+					s.next()
+				}
+			case '>':
+				tok = token.MGTH
+				s.next()
+				if s.ch == '=' {
+					tok = token.MGEQ
+					s.next()
+				}
+			}
+		case '@':
+			tok = token.AT
+		case '`':
+			tok = token.BACKTICK
+			if s.isProtectDirective() {
+				tok = token.PROTECT
+				lit = s.scanProtectDirective()
+			}
 		case '=':
 			tok = token.EQL
 			if s.ch == '>' {
@@ -458,12 +822,8 @@ func (s *Scanner) Scan() (pos token.Pos, tok token.Token, lit string) {
 			tok = token.DIV
 			if s.ch == '*' {
 				//Comment
-				comment, valid := s.scanMultipleLineComment()
-				tok = token.COMMENT
-				if !valid {
-					tok = token.ILLEGAL
-				}
-				lit = comment
+				lit = s.scanMultipleLineComment()
+				tok = commentToken(lit)
 			} else if s.ch == '=' {
 				tok = token.NEQ
 				s.next()
@@ -476,26 +836,33 @@ func (s *Scanner) Scan() (pos token.Pos, tok token.Token, lit string) {
 			} else if s.ch == '>' {
 				tok = token.BOX
 				s.next()
+			} else if s.ch == '<' {
+				//Start of a VHDL-2008 external name
+				tok = token.DOUBLE_LTH
+				s.next()
 			}
 		case '>':
 			tok = token.GTH
 			if s.ch == '=' {
 				tok = token.GEQ
 				s.next()
+			} else if s.ch == '>' {
+				//End of a VHDL-2008 external name
+				tok = token.DOUBLE_GTH
+				s.next()
 			}
 		case '|':
 			tok = token.VLINE
-			//TODO: Do rest of ? operators/delimeters
 
 		default:
-			// next reports unexpected BOMs - don't repeat
-			if ch != bom {
+			// next reports unexpected BOMs and NUL - don't repeat
+			if ch != bom && ch != 0 {
 				// Report an informative error for U+201[CD] quotation
 				// marks, which are easily introduced via copy and paste.
 				if ch == '“' || ch == '”' {
-					s.errorf(s.file.Offset(pos), "curly quotation mark %q (use neutral %q)", ch, '"')
+					s.errorf(offs, "curly quotation mark %q (use neutral %q)", ch, '"')
 				} else {
-					s.errorf(s.file.Offset(pos), "illegal character %#U", ch)
+					s.errorf(offs, "illegal character %#U", ch)
 				}
 			}
 			tok = token.ILLEGAL
@@ -505,11 +872,24 @@ func (s *Scanner) Scan() (pos token.Pos, tok token.Token, lit string) {
 
 	}
 
-	return
-}
+	if tok == token.BIT_STR {
+		s.checkBitString(offs, lit)
+	}
+
+	if since := tok.Since(); !s.std.Includes(since) {
+		//Reserved words of newer standards are already identifiers, only delimiters are left
+		s.errorf(offs, "%s requires %s or later (current standard is %s)", tok, since, s.std)
+	}
+
+	s.end = s.srcBase + s.offset
+	if s.mode&Trivia != 0 {
+		//The literal of every token is its source text
+		lit = s.text(offs, s.offset)
+		s.trailing = s.scanTrivia(true)
+	}
 
-func (s *Scanner) RevertPos(pos token.Pos) {
-    s.offset = s.file.Offset(pos)
-    s.rdOffset = s.offset
-    s.ch = rune(s.src[s.offset])
+	if tok != token.COMMENT && tok != token.PRAGMA {
+		s.prev = tok
+	}
+	return
 }
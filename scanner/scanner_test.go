@@ -0,0 +1,646 @@
+package scanner
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"vhdl/token"
+)
+
+type elt struct {
+	tok token.Token
+	lit string
+}
+
+// scanAll returns every token of src up to EOF together with the errors reported.
+func scanAll(src string, mode Mode) ([]elt, ErrorList) {
+	var s Scanner
+	var errs ErrorList
+	fset := token.NewFileSet()
+	file := fset.AddFile("test.vhd", fset.Base(), len(src))
+	s.Init(file, []byte(src), errs.Add, mode)
+	var list []elt
+	for {
+		_, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		list = append(list, elt{tok, lit})
+	}
+	return list, errs
+}
+
+func TestExtendedIdentifier(t *testing.T) {
+	tests := []struct {
+		src string
+		lit string
+	}{
+		{`\my signal\`, `\my signal\`},
+		{`\bus[3]\`, `\bus[3]\`},
+		{`\a\\b\`, `\a\\b\`},
+		{`\\\\`, `\\\\`},
+		{`\ENTITY\`, `\ENTITY\`},
+	}
+	for _, test := range tests {
+		list, errs := scanAll(test.src, 0)
+		if len(errs) != 0 {
+			t.Errorf("%s: unexpected errors: %v", test.src, errs)
+			continue
+		}
+		if len(list) != 1 || list[0].tok != token.IDENT || list[0].lit != test.lit {
+			t.Errorf("%s: got %v, want IDENT %s", test.src, list, test.lit)
+		}
+	}
+}
+
+func TestExtendedIdentifierErrors(t *testing.T) {
+	for _, src := range []string{"\\abc\n", `\\ x`, "\\a\tb\\"} {
+		if _, errs := scanAll(src, 0); len(errs) == 0 {
+			t.Errorf("%q: expected an error", src)
+		}
+	}
+}
+
+func TestExtendedIdentifierComparison(t *testing.T) {
+	if token.EqualIdent(`\Data\`, `\data\`) {
+		t.Error(`\Data\ and \data\ must be different identifiers`)
+	}
+	if token.EqualIdent(`\data\`, "data") {
+		t.Error(`\data\ and data must be different identifiers`)
+	}
+}
+
+func TestSourceEncoding(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		mode Mode
+		want []elt
+	}{
+		{"latin1 detected", "caf\xe9 -- na\xefve\n", ScanComments, []elt{{token.IDENT, "café"}, {token.COMMENT, "-- naïve"}}},
+		{"utf8 detected", "café -- naïve\n", ScanComments, []elt{{token.IDENT, "café"}, {token.COMMENT, "-- naïve"}}},
+		{"utf8 bom", "\xef\xbb\xbfcafé", 0, []elt{{token.IDENT, "café"}}},
+		{"latin1 forced", "-- é\n", Latin1 | ScanComments, []elt{{token.COMMENT, "-- Ã©"}}},
+		{"utf8 string", `"日本"`, 0, []elt{{token.STRING, `"日本"`}}},
+	}
+	for _, test := range tests {
+		list, errs := scanAll(test.src, test.mode)
+		if len(errs) != 0 {
+			t.Errorf("%s: unexpected errors: %v", test.name, errs)
+			continue
+		}
+		if len(list) != len(test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, list, test.want)
+			continue
+		}
+		for i := range list {
+			if list[i] != test.want[i] {
+				t.Errorf("%s: got %v, want %v", test.name, list[i], test.want[i])
+			}
+		}
+	}
+}
+
+func TestIdentifierLetters(t *testing.T) {
+	//µ and Greek letters are not letters of ISO/IEC 8859-1
+	for _, src := range []string{"µs", "αβ", "a\xff\xfe"} {
+		if _, errs := scanAll(src, UTF8); len(errs) == 0 {
+			t.Errorf("%q: expected an error", src)
+		}
+	}
+}
+
+func TestCharacterColumn(t *testing.T) {
+	src := "signal \"ñandú\" é\n  x"
+	var s Scanner
+	fset := token.NewFileSet()
+	file := fset.AddFile("test.vhd", fset.Base(), len(src))
+	s.Init(file, []byte(src), nil, 0)
+	want := []struct{ line, column, charColumn int }{
+		{1, 1, 1},   // signal
+		{1, 8, 8},   // "ñandú"
+		{1, 18, 16}, // é
+		{2, 3, 3},   // x
+	}
+	for _, w := range want {
+		pos, _, lit := s.Scan()
+		p := fset.Position(pos)
+		if p.Line != w.line || p.Column != w.column || p.CharColumn != w.charColumn {
+			t.Errorf("%s: got %d:%d (char %d), want %d:%d (char %d)", lit, p.Line, p.Column, p.CharColumn, w.line, w.column, w.charColumn)
+		}
+	}
+}
+
+func TestDelimiters(t *testing.T) {
+	tests := []struct {
+		src string
+		tok token.Token
+	}{
+		{"&", token.CONCAT}, {"(", token.LPAREN}, {")", token.RPAREN},
+		{"*", token.MULT}, {"+", token.PLUS}, {",", token.COMMA}, {"-", token.MINUS},
+		{".", token.DOT}, {"/", token.DIV}, {":", token.COLON}, {";", token.SEMICOLON},
+		{"<", token.LTH}, {"=", token.EQL}, {">", token.GTH}, {"`", token.BACKTICK},
+		{"|", token.VLINE}, {"[", token.LSQPAREN}, {"]", token.RSQPAREN}, {"?", token.QUEST},
+		{"@", token.AT}, {"=>", token.ARROW}, {"**", token.EXP}, {":=", token.VAR_ASSIGN},
+		{"/=", token.NEQ}, {">=", token.GEQ}, {"<=", token.LEQ_SA}, {"<>", token.BOX},
+		{"??", token.COND_CONV}, {"?=", token.MEQ}, {"?/=", token.MNEQ}, {"?<", token.MLTH},
+		{"?<=", token.MLEQ}, {"?>", token.MGTH}, {"?>=", token.MGEQ}, {"<<", token.DOUBLE_LTH},
+		{">>", token.DOUBLE_GTH},
+	}
+	for _, test := range tests {
+		list, errs := scanAll(test.src, 0)
+		if len(errs) != 0 || len(list) != 1 || list[0].tok != test.tok {
+			t.Errorf("%s: got %v (errors %v), want %s", test.src, list, errs, test.tok)
+		}
+		if test.tok.String() != test.src {
+			t.Errorf("%s: token table has %q", test.src, test.tok.String())
+		}
+	}
+}
+
+func TestExternalName(t *testing.T) {
+	src := "<< signal .tb.dut.x : std_logic >> ?/= a?<=b ?/c"
+	want := []token.Token{
+		token.DOUBLE_LTH, token.SIGNAL, token.DOT, token.IDENT, token.DOT, token.IDENT, token.DOT, token.IDENT,
+		token.COLON, token.IDENT, token.DOUBLE_GTH, token.MNEQ, token.IDENT, token.MLEQ, token.IDENT,
+		token.QUEST, token.DIV, token.IDENT,
+	}
+	list, errs := scanAll(src, 0)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(list) != len(want) {
+		t.Fatalf("got %v, want %v", list, want)
+	}
+	for i, tok := range want {
+		if list[i].tok != tok {
+			t.Errorf("token %d: got %s, want %s", i, list[i].tok, tok)
+		}
+	}
+}
+
+func TestStandardReservedWords(t *testing.T) {
+	tests := []struct {
+		src  string
+		mode Mode
+		tok  token.Token
+	}{
+		{"view", 0, token.VIEW},
+		{"view", VHDL2019, token.VIEW},
+		{"view", VHDL2008, token.IDENT},
+		{"context", VHDL2008, token.CONTEXT},
+		{"context", VHDL2002, token.IDENT},
+		{"protected", VHDL2002, token.PROTECTED},
+		{"protected", VHDL93, token.IDENT},
+		{"xnor", VHDL93, token.XNOR},
+		{"xnor", VHDL87, token.IDENT},
+	}
+	for _, test := range tests {
+		list, errs := scanAll(test.src, test.mode)
+		if len(errs) != 0 || len(list) != 1 || list[0].tok != test.tok {
+			t.Errorf("%s (%s): got %v (errors %v), want %s", test.src, test.mode.Standard(), list, errs, test.tok)
+		}
+	}
+}
+
+func TestStandardDelimiters(t *testing.T) {
+	tests := []struct {
+		src  string
+		mode Mode
+		errs int
+	}{
+		{"a ?= b", VHDL2008, 0},
+		{"a ?= b", VHDL2002, 1},
+		{"<< signal .tb.x : bit >>", VHDL93, 2},
+		{`\ext\`, VHDL93, 0},
+		{`\ext\`, VHDL87, 1},
+	}
+	for _, test := range tests {
+		if _, errs := scanAll(test.src, test.mode); len(errs) != test.errs {
+			t.Errorf("%s (%s): got errors %v, want %d", test.src, test.mode.Standard(), errs, test.errs)
+		}
+	}
+}
+
+func TestBitStringLiteral(t *testing.T) {
+	tests := []struct {
+		src  string
+		mode Mode
+		want []elt
+		errs int
+	}{
+		{`12UX"F0"`, 0, []elt{{token.BIT_STR, `12UX"F0"`}}, 0},
+		{`X"ZZ" sb"1"`, 0, []elt{{token.BIT_STR, `X"ZZ"`}, {token.BIT_STR, `sb"1"`}}, 0},
+		{`B"012"`, 0, []elt{{token.BIT_STR, `B"012"`}}, 1},
+		{`2X"FF"`, 0, []elt{{token.BIT_STR, `2X"FF"`}}, 1},
+		{`SD"1"`, 0, []elt{{token.IDENT, "SD"}, {token.STRING, `"1"`}}, 0},
+		{`4SD"1"`, 0, []elt{{token.BIT_STR, `4SD"1"`}}, 1},
+		{`8UX"F"`, VHDL2002, []elt{{token.BIT_STR, `8UX"F"`}}, 2},
+		{`10us`, 0, []elt{{token.INT, "10"}, {token.IDENT, "us"}}, 0},
+	}
+	for _, test := range tests {
+		list, errs := scanAll(test.src, test.mode)
+		if len(errs) != test.errs {
+			t.Errorf("%s: got errors %v, want %d", test.src, errs, test.errs)
+		}
+		if len(list) != len(test.want) {
+			t.Errorf("%s: got %v, want %v", test.src, list, test.want)
+			continue
+		}
+		for i := range list {
+			if list[i] != test.want[i] {
+				t.Errorf("%s: got %v, want %v", test.src, list[i], test.want[i])
+			}
+		}
+	}
+}
+
+func TestAbstractLiteralErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		tok  token.Token
+		errs []string
+	}{
+		{"16#FF#", token.BASED, nil},
+		{"1.5E-3", token.REAL, nil},
+		{"8#9#", token.BASED, []string{"1:3: invalid digit '9' in base 8 literal"}},
+		{"12#F#", token.BASED, []string{"1:4: invalid digit 'F' in base 12 literal"}},
+		{"17#1#", token.BASED, []string{"1:1: invalid base 17, the base must be between 2 and 16"}},
+		{"1E-3", token.INT, []string{"1:2: negative exponent in integer literal"}},
+		{"2#1#E-1", token.BASED, []string{"1:5: negative exponent in integer literal"}},
+	}
+	for _, test := range tests {
+		list, errs := scanAll(test.src, 0)
+		if len(list) != 1 || list[0].tok != test.tok || list[0].lit != test.src {
+			t.Errorf("%s: got %v, want %s", test.src, list, test.tok)
+		}
+		if len(errs) != len(test.errs) {
+			t.Errorf("%s: got errors %v, want %v", test.src, errs, test.errs)
+			continue
+		}
+		for i, err := range errs {
+			if got := fmt.Sprintf("%d:%d: %s", err.Pos.Line, err.Pos.Column, err.Msg); got != test.errs[i] {
+				t.Errorf("%s: got error %q, want %q", test.src, got, test.errs[i])
+			}
+		}
+	}
+}
+
+func TestStringAndCharacterLiterals(t *testing.T) {
+	tests := []struct {
+		src  string
+		want []elt
+		errs int
+	}{
+		{`"He said ""hi"""`, []elt{{token.STRING, `"He said ""hi"""`}}, 0},
+		{`"" & """"`, []elt{{token.STRING, `""`}, {token.CONCAT, ""}, {token.STRING, `""""`}}, 0},
+		{`"abc` + "\n", []elt{{token.STRING, `"abc`}}, 1},
+		{`c := ''';`, []elt{{token.IDENT, "c"}, {token.VAR_ASSIGN, ""}, {token.CHAR, "'''"}, {token.SEMICOLON, ";"}}, 0},
+		{`character'('a')`, []elt{{token.IDENT, "character"}, {token.APOS, ""}, {token.LPAREN, ""}, {token.CHAR, "'a'"}, {token.RPAREN, ""}}, 0},
+		{`t'(''')`, []elt{{token.IDENT, "t"}, {token.APOS, ""}, {token.LPAREN, ""}, {token.CHAR, "'''"}, {token.RPAREN, ""}}, 0},
+		{`a(1)'length`, []elt{{token.IDENT, "a"}, {token.LPAREN, ""}, {token.INT, "1"}, {token.RPAREN, ""}, {token.APOS, ""}, {token.IDENT, "length"}}, 0},
+		{`x = 'a' -- c` + "\nor 'b'", []elt{{token.IDENT, "x"}, {token.EQL, ""}, {token.CHAR, "'a'"}, {token.COMMENT, "-- c"}, {token.OR, "or"}, {token.CHAR, "'b'"}}, 0},
+	}
+	for _, test := range tests {
+		list, errs := scanAll(test.src, 0)
+		if len(errs) != test.errs {
+			t.Errorf("%s: got errors %v, want %d", test.src, errs, test.errs)
+		}
+		if len(list) != len(test.want) {
+			t.Errorf("%s: got %v, want %v", test.src, list, test.want)
+			continue
+		}
+		for i := range list {
+			if list[i] != test.want[i] {
+				t.Errorf("%s: got %v, want %v", test.src, list[i], test.want[i])
+			}
+		}
+	}
+}
+
+func TestTrivia(t *testing.T) {
+	src := "-- header\r\nentity  E is /* block\n */ end; -- done\n\n\tarchitecture A\n"
+	var s Scanner
+	fset := token.NewFileSet()
+	file := fset.AddFile("test.vhd", fset.Base(), len(src))
+	s.Init(file, []byte(src), nil, Trivia)
+
+	type trivia struct{ leading, lit, trailing string }
+	want := []trivia{
+		{"-- header\r\n", "entity", "  "},
+		{"", "E", " "},
+		{"", "is", " /* block\n */ "},
+		{"", "end", ""},
+		{"", ";", " -- done\n"},
+		{"\n\t", "architecture", " "},
+		{"", "A", "\n"},
+		{"", "", ""},
+	}
+	var sb strings.Builder
+	for i := 0; ; i++ {
+		_, tok, lit := s.Scan()
+		leading, trailing := s.Trivia()
+		if i < len(want) {
+			if got := (trivia{leading, lit, trailing}); got != want[i] {
+				t.Errorf("token %d: got %q, want %q", i, got, want[i])
+			}
+		}
+		sb.WriteString(leading + lit + trailing)
+		if tok == token.EOF {
+			break
+		}
+	}
+	if sb.String() != src {
+		t.Errorf("got source %q, want %q", sb.String(), src)
+	}
+}
+
+func TestProtectDirectives(t *testing.T) {
+	src := "`protect begin_protected\n" +
+		"`protect encoding = (enctype = \"BASE64\", line_length = 76, bytes = 16)\n" +
+		"`Protect data_block\n" +
+		"q2/+9SJ1sa==\r\n" +
+		"ZZ9+//\r\n" +
+		"`protect end_protected\n" +
+		"end"
+	list, errs := scanAll(src, 0)
+	if len(errs) != 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	want := []elt{
+		{token.PROTECT, "`protect begin_protected"},
+		{token.PROTECT, "`protect encoding = (enctype = \"BASE64\", line_length = 76, bytes = 16)"},
+		{token.PROTECT, "`Protect data_block"},
+		{token.PROTECT_DATA, "q2/+9SJ1sa==\r\nZZ9+//"},
+		{token.PROTECT, "`protect end_protected"},
+		{token.END, "end"},
+	}
+	if len(list) != len(want) {
+		t.Fatalf("got %v, want %v", list, want)
+	}
+	for i := range list {
+		if list[i] != want[i] {
+			t.Errorf("got %v, want %v", list[i], want[i])
+		}
+	}
+
+	if _, errs := scanAll("`protect begin_protected\n", VHDL2002); len(errs) != 1 {
+		t.Errorf("got errors %v, want a standard error", errs)
+	}
+}
+
+func TestProtectUuencode(t *testing.T) {
+	//A uuencode block ends with a line holding a lone '`', data lines may start with it too
+	src := "`protect begin_protected\n" +
+		"`protect encoding = (enctype = \"UUENCODE\")\n" +
+		"`protect data_block\n" +
+		"`%:&5L;&\\*\n" +
+		"%:&5L;&\\*\n" +
+		"`\n" +
+		"`protect end_protected\n"
+	list, errs := scanAll(src, 0)
+	if len(errs) != 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	want := []elt{
+		{token.PROTECT, "`protect begin_protected"},
+		{token.PROTECT, "`protect encoding = (enctype = \"UUENCODE\")"},
+		{token.PROTECT, "`protect data_block"},
+		{token.PROTECT_DATA, "`%:&5L;&\\*\n%:&5L;&\\*\n`"},
+		{token.PROTECT, "`protect end_protected"},
+	}
+	if len(list) != len(want) {
+		t.Fatalf("got %v, want %v", list, want)
+	}
+	for i := range list {
+		if list[i] != want[i] {
+			t.Errorf("got %v, want %v", list[i], want[i])
+		}
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	tests := []struct {
+		src  string
+		want []elt
+		errs []string
+	}{
+		{"a /* open\nb", []elt{{token.IDENT, "a"}, {token.COMMENT, "/* open\nb"}}, []string{"test.vhd:1:3: comment not terminated"}},
+		{"a -- last", []elt{{token.IDENT, "a"}, {token.COMMENT, "-- last"}}, nil},
+		{"16#FF + 1", []elt{{token.BASED, "16#FF"}, {token.PLUS, ""}, {token.INT, "1"}}, []string{"test.vhd:1:6: based literal not terminated, expected '#'"}},
+		{"2#102#", []elt{{token.BASED, "2#102#"}}, []string{"test.vhd:1:5: invalid digit '2' in base 2 literal"}},
+		{"1__0 2_", []elt{{token.INT, "1__0"}, {token.INT, "2_"}}, []string{"test.vhd:1:3: underline must separate two digits", "test.vhd:1:7: underline must separate two digits"}},
+		{"a\x00b", []elt{{token.IDENT, "a"}, {token.ILLEGAL, "\x00"}, {token.IDENT, "b"}}, []string{"test.vhd:1:2: illegal character NUL"}},
+		{"c := '\t';", []elt{{token.IDENT, "c"}, {token.VAR_ASSIGN, ""}, {token.CHAR, "'\t'"}, {token.SEMICOLON, ";"}}, []string{"test.vhd:1:7: invalid character U+0009 in character literal"}},
+		{"\"open\nx", []elt{{token.STRING, "\"open"}, {token.IDENT, "x"}}, []string{"test.vhd:1:1: string literal not terminated"}},
+	}
+	for _, test := range tests {
+		list, errs := scanAll(test.src, 0)
+		if len(errs) != len(test.errs) {
+			t.Errorf("%q: got errors %v, want %v", test.src, errs, test.errs)
+		} else {
+			for i, err := range errs {
+				if err.Error() != test.errs[i] {
+					t.Errorf("%q: got error %q, want %q", test.src, err.Error(), test.errs[i])
+				}
+			}
+		}
+		if len(list) != len(test.want) {
+			t.Errorf("%q: got %v, want %v", test.src, list, test.want)
+			continue
+		}
+		for i := range list {
+			if list[i] != test.want[i] {
+				t.Errorf("%q: got %v, want %v", test.src, list[i], test.want[i])
+			}
+		}
+	}
+}
+
+func TestStreamingScanner(t *testing.T) {
+	unit := "entity Café is -- commentaire\n" +
+		"  port (a : in bit_vector(7 downto 0) := X\"F0\"; b : out real := 1.5E-3);\n" +
+		"end entity Café; /* bloc\n sur deux lignes */ c <= 'x' & \"str\"\"ing\";\n" +
+		"`protect data_block\nQUJDRA==\n`protect end_protected\n"
+	src := strings.Repeat(unit, 3*streamChunk/len(unit)+1)
+
+	type scanned struct {
+		pos token.Position
+		tok token.Token
+		lit string
+	}
+	scanSource := func(init func(s *Scanner, fset *token.FileSet, errs *ErrorList)) ([]scanned, ErrorList, *Scanner) {
+		var s Scanner
+		var errs ErrorList
+		fset := token.NewFileSet()
+		init(&s, fset, &errs)
+		var list []scanned
+		for {
+			pos, tok, lit := s.Scan()
+			list = append(list, scanned{fset.Position(pos), tok, lit})
+			if tok == token.EOF {
+				break
+			}
+		}
+		return list, errs, &s
+	}
+
+	want, wantErrs, _ := scanSource(func(s *Scanner, fset *token.FileSet, errs *ErrorList) {
+		s.Init(fset.AddFile("test.vhd", -1, len(src)), []byte(src), errs.Add, 0)
+	})
+	for _, reader := range []func(io.Reader) io.Reader{iotest.HalfReader, iotest.OneByteReader} {
+		got, errs, s := scanSource(func(s *Scanner, fset *token.FileSet, errs *ErrorList) {
+			//Nothing reserved up front, the last file of the set grows as it is read
+			s.InitReader(fset.AddStreamFile("test.vhd", -1, 0), reader(strings.NewReader(src)), errs.Add, 0)
+		})
+		if len(errs) != len(wantErrs) {
+			t.Errorf("got errors %v, want %v", errs, wantErrs)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("got %d tokens, want %d", len(got), len(want))
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Fatalf("token %d: got %v, want %v", i, got[i], want[i])
+			}
+		}
+		if s.file.Size() != len(src) {
+			t.Errorf("got file size %d, want %d", s.file.Size(), len(src))
+		}
+		if cap(s.src) > 4*streamChunk {
+			t.Errorf("buffer grew to %d bytes", cap(s.src))
+		}
+	}
+
+	//A file added after the streamed one limits it to the size reserved so far
+	_, errs, _ := scanSource(func(s *Scanner, fset *token.FileSet, errs *ErrorList) {
+		s.InitReader(fset.AddStreamFile("test.vhd", -1, 100), strings.NewReader(src), errs.Add, 0)
+		fset.AddFile("next.vhd", -1, 10)
+	})
+	if len(errs) == 0 || errs[len(errs)-1].Msg != "source exceeds the size reserved for the file" {
+		t.Errorf("got errors %v, want a size error", errs)
+	}
+}
+
+func TestKeepContent(t *testing.T) {
+	src := "a <= b;\nc"
+	var s Scanner
+	file := token.NewFileSet().AddFile("test.vhd", -1, len(src))
+	s.Init(file, []byte(src), nil, KeepContent)
+	pos, _, _ := s.Scan()
+	s.Scan()
+	if got := file.Text(pos, s.End()); got != "a <=" {
+		t.Errorf("got text %q, want %q", got, "a <=")
+	}
+}
+
+func TestCheckpoint(t *testing.T) {
+	src := "a := f(x)'length;\n-- comment\n\"open\nb 8#9# é\nc"
+	var s Scanner
+	var errs ErrorList
+	fset := token.NewFileSet()
+	file := fset.AddFile("test.vhd", fset.Base(), len(src))
+	s.Init(file, []byte(src), errs.Add, 0)
+	for i := 0; i < 4; i++ {
+		s.Scan()
+	}
+	c := s.Checkpoint()
+	lines := file.LineCount()
+
+	scanRest := func() (list []string) {
+		for {
+			pos, tok, lit := s.Scan()
+			list = append(list, fmt.Sprintf("%v %s %q", fset.Position(pos), tok, lit))
+			if tok == token.EOF {
+				return
+			}
+		}
+	}
+	want := scanRest()
+	wantErrors := s.ErrorCount
+	s.Restore(c)
+	if file.LineCount() != lines || s.ErrorCount != 0 {
+		t.Errorf("restored %d lines and %d errors, want %d lines and no errors", file.LineCount(), s.ErrorCount, lines)
+	}
+	got := scanRest()
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if s.ErrorCount != wantErrors || file.LineCount() != 5 {
+		t.Errorf("got %d errors and %d lines, want %d errors and 5 lines", s.ErrorCount, file.LineCount(), wantErrors)
+	}
+}
+
+func TestTokens(t *testing.T) {
+	src := "signal s : bit; -- state\n  s <= '1';\n"
+	fset := token.NewFileSet()
+	file := fset.AddFile("test.vhd", fset.Base(), len(src))
+	tokens := Tokenize(file, []byte(src), nil, Trivia)
+	if n := len(tokens); n != 10 || tokens[n-1].Kind != token.EOF {
+		t.Fatalf("got %d tokens ending with %v, want 10 ending with EOF", n, tokens[n-1])
+	}
+	var sb strings.Builder
+	for _, tok := range tokens {
+		if text := src[file.Offset(tok.Pos):file.Offset(tok.End)]; text != tok.Lit {
+			t.Errorf("%s: source %q at [%d, %d), want %q", tok.Kind, text, tok.Pos, tok.End, tok.Lit)
+		}
+		sb.WriteString(tok.Leading + tok.Lit + tok.Trailing)
+	}
+	if sb.String() != src {
+		t.Errorf("got source %q, want %q", sb.String(), src)
+	}
+	if tok := tokens[4]; tok.Kind != token.SEMICOLON || tok.Trailing != " -- state\n" || tokens[5].Leading != "  " {
+		t.Errorf("got %v and %v", tok, tokens[5])
+	}
+
+	//Breaking out of the loop stops scanning
+	var s Scanner
+	s.Init(fset.AddFile("test.vhd", fset.Base(), len(src)), []byte(src), nil, 0)
+	for tok := range s.Tokens() {
+		if tok.Kind == token.COLON {
+			break
+		}
+	}
+	if _, tok, _ := s.Scan(); tok != token.IDENT {
+		t.Errorf("got %s after the loop, want IDENT bit", tok)
+	}
+}
+
+func TestLineDirective(t *testing.T) {
+	src := "a\n--line tpl/entity.j2:40:3\nb c\n--line other.j2:7\nd\n -- line x.j2:1\ne\n--line bad.j2:x0\n--line counter: counts lines\nf\n"
+	scan := func(mode Mode) (string, ErrorList) {
+		var s Scanner
+		var errs ErrorList
+		fset := token.NewFileSet()
+		s.Init(fset.AddFile("gen.vhd", fset.Base(), len(src)), []byte(src), errs.Add, mode)
+		var got []string
+		for {
+			pos, tok, lit := s.Scan()
+			if tok == token.EOF {
+				break
+			}
+			if tok == token.IDENT {
+				position := fset.Position(pos)
+				got = append(got, lit+"@"+position.String())
+			}
+		}
+		return strings.Join(got, " "), errs
+	}
+
+	got, errs := scan(LineDirectives)
+	want := "a@gen.vhd:1:1 b@tpl/entity.j2:40:3 c@tpl/entity.j2:40:5 d@other.j2:7 e@other.j2:9 f@other.j2:12"
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if len(errs) != 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	//Without the mode a --line comment is an ordinary comment
+	got, errs = scan(0)
+	want = "a@gen.vhd:1:1 b@gen.vhd:3:1 c@gen.vhd:3:3 d@gen.vhd:5:1 e@gen.vhd:7:1 f@gen.vhd:10:1"
+	if got != want || len(errs) != 0 {
+		t.Errorf("got %s and errors %v, want %s", got, errs, want)
+	}
+}
@@ -0,0 +1,96 @@
+package scanner
+
+import (
+	"fmt"
+	"io"
+	"unicode/utf8"
+	"vhdl/token"
+)
+
+const (
+	streamChunk   = 64 << 10 // bytes read at once from a streamed source
+	maxEmptyReads = 100      // reads without data before giving up, see bufio
+)
+
+// InitReader prepares the scanner to tokenize the source read from r, like Init does
+// for a source in memory. Only the part of the source around the current token is
+// buffered, so sources larger than the memory available can be scanned. The file must
+// be added with token.FileSet.AddStreamFile, it grows as the source is read. The source
+// can exceed the size reserved for the file only while it is the last file of its set,
+// otherwise scanning stops there with an error.
+func (s *Scanner) InitReader(file *token.File, r io.Reader, err ErrorHandler, mode Mode) {
+	if file.Size() != 0 {
+		panic(fmt.Sprintf("streamed file size (%d) must start at 0", file.Size()))
+	}
+	s.src = make([]byte, 0, streamChunk)
+	s.reader = r
+	s.init(file, err, mode)
+}
+
+// fill reports whether n bytes after the reading offset are buffered, it reads the
+// source of a streaming scanner until they are or the source ends.
+func (s *Scanner) fill(n int) bool {
+	for s.reader != nil && s.rdOffset+n > len(s.src) {
+		if len(s.src) == cap(s.src) {
+			//The buffer starts with the current token, a long token needs a larger buffer
+			src := make([]byte, len(s.src), 2*cap(s.src)+streamChunk)
+			copy(src, s.src)
+			s.src = src
+		}
+		end := cap(s.src)
+		if !s.file.Reserve(s.srcBase + end) {
+			end = s.file.MaxSize() - s.srcBase
+		}
+		if len(s.src) >= end {
+			s.error(len(s.src), "source exceeds the size reserved for the file")
+			s.reader = nil
+			break
+		}
+		m, err := s.reader.Read(s.src[len(s.src):end])
+		s.src = s.src[:len(s.src)+m]
+		s.file.Grow(s.srcBase + len(s.src))
+		switch {
+		case err == io.EOF:
+			s.reader = nil
+		case err != nil:
+			s.error(len(s.src), err.Error())
+			s.reader = nil
+		case m > 0:
+			s.empty = 0
+		default:
+			if s.empty++; s.empty >= maxEmptyReads {
+				s.error(len(s.src), io.ErrNoProgress.Error())
+				s.reader = nil
+			}
+		}
+	}
+	return s.rdOffset+n <= len(s.src)
+}
+
+// discard drops the buffered source before the current character, it is only called
+// between tokens. The buffer is compacted once half of it is no longer needed.
+func (s *Scanner) discard() {
+	n := s.offset
+	if s.reader == nil || n < cap(s.src)/2 {
+		return
+	}
+	copy(s.src, s.src[n:])
+	s.src = s.src[:len(s.src)-n]
+	s.srcBase += n
+	s.offset -= n
+	s.rdOffset -= n
+	s.lineOffset -= n
+}
+
+// completeRunes returns src without an incomplete UTF-8 sequence at its end.
+func completeRunes(src []byte) []byte {
+	for n := 1; n < utf8.UTFMax && n <= len(src); n++ {
+		if utf8.RuneStart(src[len(src)-n]) {
+			if !utf8.FullRune(src[len(src)-n:]) {
+				return src[:len(src)-n]
+			}
+			break
+		}
+	}
+	return src
+}
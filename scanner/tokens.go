@@ -0,0 +1,42 @@
+package scanner
+
+import (
+	"iter"
+	"slices"
+	"vhdl/token"
+)
+
+// Token is a token returned by Scan with the extent of its source text.
+type Token struct {
+	Kind     token.Token
+	Lit      string
+	Pos      token.Pos // position of the first character
+	End      token.Pos // position immediately after the token
+	Leading  string    // trivia before the token, only in Trivia mode
+	Trailing string    // trivia after the token, only in Trivia mode
+}
+
+// Tokens returns an iterator over the remaining tokens of the source, the last one is
+// EOF. Ranging over it calls Scan, the errors go to the handler given to Init.
+//
+//	for tok := range s.Tokens() {
+//		fmt.Println(fset.Position(tok.Pos), tok.Kind, tok.Lit)
+//	}
+func (s *Scanner) Tokens() iter.Seq[Token] {
+	return func(yield func(Token) bool) {
+		for {
+			pos, kind, lit := s.Scan()
+			tok := Token{Kind: kind, Lit: lit, Pos: pos, End: s.End(), Leading: s.leading, Trailing: s.trailing}
+			if !yield(tok) || kind == token.EOF {
+				return
+			}
+		}
+	}
+}
+
+// Tokenize scans src and returns all its tokens up to EOF included, the arguments are the ones of Init.
+func Tokenize(file *token.File, src []byte, err ErrorHandler, mode Mode) []Token {
+	var s Scanner
+	s.Init(file, src, err, mode)
+	return slices.Collect(s.Tokens())
+}
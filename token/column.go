@@ -0,0 +1,87 @@
+package token
+
+import "sort"
+
+// ColumnUnit is what a column counts, editors do not agree on it.
+type ColumnUnit int
+
+const (
+	ByteColumn  ColumnUnit = iota // bytes of the UTF-8 encoding, like Position.Column
+	RuneColumn                    // characters, like Position.CharColumn
+	UTF16Column                   // UTF-16 code units, used by the Language Server Protocol
+)
+
+// LineColumn returns the line and the column of p, both starting at 1, with the column
+// counted in unit. The characters are the ones recorded by the scanner or SetContent.
+func (f *File) LineColumn(p Pos, unit ColumnUnit) (line, column int) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if !p.IsValid() {
+		return 0, 0
+	}
+	offset := f.Offset(p)
+	index := f.lineIndex(offset)
+	if index < 0 {
+		return 0, 0
+	}
+	start := f.line_start[index]
+	column = offset - start + 1
+	switch unit {
+	case RuneColumn:
+		column -= f.extraBytes(start, offset)
+	case UTF16Column:
+		//A character of 4 bytes in UTF-8 is a surrogate pair in UTF-16
+		column -= f.extraBytes(start, offset) - f.supplementary(start, offset)
+	}
+	return index + 1, column
+}
+
+// LinePos is the inverse of LineColumn, it returns the position of column, counted in
+// unit, of line. A column past the end of the line is the end of the line, a column in
+// the middle of a character is the start of the character. LinePos returns NoPos if
+// there is no such line.
+func (f *File) LinePos(line, column int, unit ColumnUnit) Pos {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if line < 1 || line > len(f.line_start) || column < 1 {
+		return NoPos
+	}
+	start, end := f.line_start[line-1], f.size
+	if line < len(f.line_start) {
+		//The line ends on its '\n'
+		end = f.line_start[line] - 1
+	}
+	if unit == ByteColumn {
+		return Pos(f.base + min(start+column-1, end))
+	}
+	offset := start
+	i := sort.Search(len(f.multibyte), func(i int) bool { return f.multibyte[i].Offset >= start })
+	for n := column - 1; n > 0 && offset < end; {
+		size, units := 1, 1
+		if i < len(f.multibyte) && f.multibyte[i].Offset == offset {
+			size = f.multibyte[i].Size
+			if size == 4 && unit == UTF16Column {
+				units = 2
+			}
+			i++
+		}
+		if n < units {
+			break
+		}
+		n -= units
+		offset += size
+	}
+	return Pos(f.base + min(offset, end))
+}
+
+// supplementary returns the number of characters in [start, end) outside of the
+// Basic Multilingual Plane, they take 4 bytes in UTF-8.
+func (f *File) supplementary(start, end int) (n int) {
+	i := sort.Search(len(f.multibyte), func(i int) bool { return f.multibyte[i].Offset >= start })
+	for ; i < len(f.multibyte) && f.multibyte[i].Offset < end; i++ {
+		if f.multibyte[i].Size == 4 {
+			n++
+		}
+	}
+	return
+}
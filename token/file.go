@@ -5,14 +5,19 @@ import (
 	"slices"
 	"sort"
 	"sync"
+	"unicode/utf8"
 )
 
 type File struct {
 	filename    string
 	base        int
 	size        int
+	limit       int      // size reserved for a streamed file, otherwise size
+	set         *FileSet // set of a streamed file, it can reserve more while it is the last
 	line_start  []int
 	column_info []columnInfo
+	multibyte   []multiByteChar // characters encoded with more than one byte, sorted by offset
+	content     []byte          // source content, or nil
 
 	//This handler can be used by multiple go corutines a mutex is need or semaphore to control access
 	mutex sync.Mutex
@@ -25,6 +30,13 @@ type columnInfo struct {
 	Column   int
 }
 
+// multiByteChar records a character that takes more than one byte of the source,
+// it is what makes a byte column differ from a character column.
+type multiByteChar struct {
+	Offset int // offset of the first byte
+	Size   int // number of bytes
+}
+
 func (f *File) AddLine(offset int) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
@@ -34,15 +46,36 @@ func (f *File) AddLine(offset int) {
 	}
 }
 
+// AddLineInfo is like AddLineColumnInfo with a column of 1.
+func (f *File) AddLineInfo(offset int, filename string, line int) {
+	f.AddLineColumnInfo(offset, filename, line, 1)
+}
+
+// AddLineColumnInfo remaps the source from offset on: the character at offset is at
+// line and column of filename, as if a line directive preceded it. The following lines
+// are numbered from there until the next remapping. Generated sources use it to report
+// the positions of their templates, see PositionFor. A column of 0 means the columns
+// are unknown. Offsets must be added in increasing order, otherwise they are ignored.
 func (f *File) AddLineColumnInfo(offset int, filename string, line, column int) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 	prevColumnInfo := len(f.column_info) - 1
-	if offset < f.size && (prevColumnInfo < 0 || f.line_start[prevColumnInfo] < offset) {
+	if offset < f.size && (prevColumnInfo < 0 || f.column_info[prevColumnInfo].Offset < offset) {
 		f.column_info = append(f.column_info, columnInfo{offset, filename, line, column})
 	}
 }
 
+// AddMultiByteChar records that the character at offset is encoded with size bytes.
+// Offsets must be added in increasing order, otherwise they are ignored.
+func (f *File) AddMultiByteChar(offset, size int) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	last := len(f.multibyte) - 1
+	if size > 1 && offset < f.size && (last < 0 || f.multibyte[last].Offset < offset) {
+		f.multibyte = append(f.multibyte, multiByteChar{offset, size})
+	}
+}
+
 func (f *File) Base() int {
 	return f.base
 }
@@ -115,17 +148,16 @@ func (f *File) Offset(p Pos) int {
 
 // Inverse of Offset
 func (f *File) Pos(offset int) Pos {
-	index := offset + f.base
-
-	if index > f.size {
-		return Pos(f.size)
+	if offset > f.size {
+		//Offsets past the end of the file are clamped to the EOF position
+		offset = f.size
 	}
 
-	if index < f.base {
+	if offset < 0 {
 		return Pos(0)
 	}
 
-	return Pos(index)
+	return Pos(f.base + offset)
 
 }
 
@@ -133,6 +165,9 @@ func (f *File) Position(p Pos) (pos Position) {
 	return f.PositionFor(p, true)
 }
 
+// PositionFor returns the Position of p. If adjusted is set, the position is remapped
+// by the line and column information added with AddLineColumnInfo, otherwise it is
+// the position in the file itself.
 func (f *File) PositionFor(p Pos, adjusted bool) (pos Position) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
@@ -143,15 +178,50 @@ func (f *File) PositionFor(p Pos, adjusted bool) (pos Position) {
 
 	pos.Offset = f.Offset(p)
 	pos.Filename = f.filename
-	//TODO adjusted and line info
-	if index := sort.Search(len(f.line_start), func(i int) bool { return f.line_start[i] > pos.Offset }) - 1; index >= 0 {
-		pos.Line, pos.Column = f.line_start[index]+1, pos.Offset-f.line_start[index]+1
+	index := f.lineIndex(pos.Offset)
+	if index < 0 {
+		return
+	}
+	pos.Line, pos.Column = index+1, pos.Offset-f.line_start[index]+1
+	pos.CharColumn = pos.Column - f.extraBytes(f.line_start[index], pos.Offset)
+
+	if !adjusted {
+		return
+	}
+	if i := sort.Search(len(f.column_info), func(i int) bool { return f.column_info[i].Offset > pos.Offset }) - 1; i >= 0 {
+		info := &f.column_info[i]
+		pos.Filename = info.Filename
+		//Lines and columns are counted from the remapped character
+		d := index - f.lineIndex(info.Offset)
+		pos.Line = info.Line + d
+		switch {
+		case info.Column == 0:
+			pos.Column, pos.CharColumn = 0, 0
+		case d == 0:
+			pos.Column = info.Column + pos.Offset - info.Offset
+			pos.CharColumn = info.Column + pos.Offset - info.Offset - f.extraBytes(info.Offset, pos.Offset)
+		}
+	}
+	return
+}
+
+// lineIndex returns the index of the line containing offset, or -1.
+func (f *File) lineIndex(offset int) int {
+	return sort.Search(len(f.line_start), func(i int) bool { return f.line_start[i] > offset }) - 1
+}
+
+// extraBytes returns the number of bytes in [start, end) beyond the first byte of
+// each multi-byte character.
+func (f *File) extraBytes(start, end int) (extra int) {
+	i := sort.Search(len(f.multibyte), func(i int) bool { return f.multibyte[i].Offset >= start })
+	for ; i < len(f.multibyte) && f.multibyte[i].Offset < end; i++ {
+		extra += f.multibyte[i].Size - 1
 	}
 	return
 }
 
 func (f *File) SetLines(lines []int) bool {
-	if slices.IsSorted(lines) {
+	if !slices.IsSorted(lines) {
 		return false
 	}
 	f.mutex.Lock()
@@ -178,6 +248,83 @@ func (f *File) SetLinesForContent(content []byte) {
 	f.line_start = lines
 }
 
+// SetContent keeps the source content of the file, it must have the size of the file.
+// The line table and the multi-byte characters are set from the content, decoded as
+// UTF-8: an invalid byte, such as a ISO/IEC 8859-1 letter, is one character.
+func (f *File) SetContent(content []byte) {
+	if len(content) != f.size {
+		panic(fmt.Sprintf("content size (%d) does not match file size (%d)", len(content), f.size))
+	}
+	lines := []int{0}
+	var multibyte []multiByteChar
+	for offset := 0; offset < len(content); {
+		r, w := rune(content[offset]), 1
+		if r >= utf8.RuneSelf {
+			r, w = utf8.DecodeRune(content[offset:])
+			if w > 1 {
+				multibyte = append(multibyte, multiByteChar{offset, w})
+			}
+		}
+		offset += w
+		if r == '\n' && offset < len(content) {
+			lines = append(lines, offset)
+		}
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.content = content
+	f.line_start = lines
+	f.multibyte = multibyte
+}
+
 func (f *File) Size() int {
 	return f.size
 }
+
+// MaxSize returns the size a file added with FileSet.AddStreamFile can grow to,
+// it is the size of any other file. It increases when Reserve succeeds.
+func (f *File) MaxSize() int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.limit
+}
+
+// Reserve extends the positions reserved for a file added with FileSet.AddStreamFile
+// to size bytes, and reports whether the file can grow to size. Positions can only be
+// added while the file is the last one of its FileSet: once another file is added
+// after it, the file is limited to the size reserved so far.
+func (f *File) Reserve(size int) bool {
+	if f.set == nil {
+		return size <= f.MaxSize()
+	}
+	f.set.mutex.Lock()
+	defer f.set.mutex.Unlock()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if size <= f.limit {
+		return true
+	}
+	if n := len(f.set.files); n == 0 || f.set.files[n-1] != f {
+		return false
+	}
+	base := f.base + size + 1 // +1 because EOF also has a position
+	if base < 0 {
+		return false
+	}
+	f.limit = size
+	f.set.base = base
+	return true
+}
+
+// Grow extends a file added with FileSet.AddStreamFile to size bytes as its source is
+// read. The size never shrinks and can not exceed MaxSize.
+func (f *File) Grow(size int) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if size > f.limit {
+		panic(fmt.Sprintf("file size %d exceeds the reserved size %d", size, f.limit))
+	}
+	if size > f.size {
+		f.size = size
+	}
+}
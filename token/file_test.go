@@ -0,0 +1,128 @@
+package token
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPositionForAdjusted(t *testing.T) {
+	src := "a\nbb\nccc\ndddd\n"
+	fset := NewFileSet()
+	f := fset.AddFile("gen.vhd", -1, len(src))
+	f.SetLinesForContent([]byte(src))
+	f.AddLineColumnInfo(2, "tpl.j2", 10, 5)  // "bb" is at 10:5 of the template
+	f.AddLineColumnInfo(5, "other.j2", 1, 0) // the next lines have no column
+	f.AddLineColumnInfo(4, "ignored.j2", 1, 1)
+
+	tests := []struct {
+		offset   int
+		adjusted bool
+		want     string
+	}{
+		{0, true, "gen.vhd:1:1"},
+		{3, true, "tpl.j2:10:6"},
+		{3, false, "gen.vhd:2:2"},
+		{7, true, "other.j2:1"},
+		{10, true, "other.j2:2"},
+		{10, false, "gen.vhd:4:2"},
+	}
+	for _, test := range tests {
+		pos := fset.PositionFor(f.Pos(test.offset), test.adjusted)
+		if got := pos.String(); got != test.want {
+			t.Errorf("offset %d adjusted %v: got %s, want %s", test.offset, test.adjusted, got, test.want)
+		}
+	}
+}
+
+func TestLineColumn(t *testing.T) {
+	src := "a é 😀 b\nx"
+	fset := NewFileSet()
+	f := fset.AddFile("test.vhd", -1, len(src))
+	f.SetContent([]byte(src))
+
+	tests := []struct {
+		offset int
+		unit   ColumnUnit
+		line   int
+		column int
+	}{
+		{10, ByteColumn, 1, 11},
+		{10, RuneColumn, 1, 7},
+		{10, UTF16Column, 1, 8},
+		{9, UTF16Column, 1, 7},
+		{4, UTF16Column, 1, 4},
+		{12, UTF16Column, 2, 1},
+	}
+	for _, test := range tests {
+		line, column := f.LineColumn(f.Pos(test.offset), test.unit)
+		if line != test.line || column != test.column {
+			t.Errorf("offset %d unit %d: got %d:%d, want %d:%d", test.offset, test.unit, line, column, test.line, test.column)
+		}
+		if p := f.LinePos(test.line, test.column, test.unit); p != f.Pos(test.offset) {
+			t.Errorf("%d:%d unit %d: got offset %d, want %d", test.line, test.column, test.unit, f.Offset(p), test.offset)
+		}
+	}
+
+	//Inside a surrogate pair and past the end of the line
+	if p := f.LinePos(1, 6, UTF16Column); f.Offset(p) != 5 {
+		t.Errorf("got offset %d, want the start of the emoji at 5", f.Offset(p))
+	}
+	if p := f.LinePos(1, 100, RuneColumn); f.Offset(p) != 11 {
+		t.Errorf("got offset %d, want the end of the line at 11", f.Offset(p))
+	}
+	if p := f.LinePos(3, 1, RuneColumn); p != NoPos {
+		t.Errorf("got %d for a missing line, want NoPos", p)
+	}
+}
+
+func TestCodeFrame(t *testing.T) {
+	src := "entity E is\n\tport (a : bitt; é : bit);\nend;\n"
+	fset := NewFileSet()
+	f := fset.AddFile("test.vhd", -1, len(src))
+	if frame := f.CodeFrame(f.Pos(0), f.Pos(6), 1); frame != "" {
+		t.Errorf("got frame %q without content", frame)
+	}
+	f.SetContent([]byte(src))
+
+	start := strings.Index(src, "bitt")
+	if got := f.Text(f.Pos(start), f.Pos(start+4)); got != "bitt" {
+		t.Errorf("got text %q, want bitt", got)
+	}
+	if got := f.LineText(2); got != "\tport (a : bitt; é : bit);" {
+		t.Errorf("got line %q", got)
+	}
+
+	tests := []struct {
+		start, end int
+		context    int
+		want       string
+	}{
+		{start, start + 4, 1, "1 | entity E is\n2 | \tport (a : bitt; é : bit);\n  | \t          ^^^^\n3 | end;\n"},
+		{strings.Index(src, "é"), strings.Index(src, "é") + 2, 0, "2 | \tport (a : bitt; é : bit);\n  | \t                ^\n"},
+		{7, 7, 0, "1 | entity E is\n  |        ^\n"},
+		{7, 13, 0, "1 | entity E is\n  |        ^^^^\n2 | \tport (a : bitt; é : bit);\n  | ^\n"},
+	}
+	for _, test := range tests {
+		if got := f.CodeFrame(f.Pos(test.start), f.Pos(test.end), test.context); got != test.want {
+			t.Errorf("[%d, %d): got\n%s\nwant\n%s", test.start, test.end, got, test.want)
+		}
+	}
+}
+
+func TestStreamFileReserve(t *testing.T) {
+	fset := NewFileSet()
+	f := fset.AddStreamFile("a.vhd", -1, 10)
+	if !f.Reserve(100) || f.MaxSize() != 100 || fset.Base() != f.Base()+101 {
+		t.Fatalf("last file: got max size %d and set base %d", f.MaxSize(), fset.Base())
+	}
+	g := fset.AddFile("b.vhd", -1, 5)
+	if g.Base() != f.Base()+101 {
+		t.Errorf("got base %d after the reserved positions, want %d", g.Base(), f.Base()+101)
+	}
+	if f.Reserve(200) || f.MaxSize() != 100 {
+		t.Errorf("file followed by another one grew to %d", f.MaxSize())
+	}
+	if !f.Reserve(50) {
+		t.Errorf("size within the reserved positions refused")
+	}
+}
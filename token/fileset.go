@@ -18,7 +18,7 @@ func NewFileSet() *FileSet {
 }
 
 func (s *FileSet) AddFile(filename string, base, size int) *File {
-	newFile := &File{filename: filename, base: base, size: size}
+	newFile := &File{filename: filename, base: base, size: size, limit: size, line_start: []int{0}}
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 	if base < 0 {
@@ -41,6 +41,18 @@ func (s *FileSet) AddFile(filename string, base, size int) *File {
 	return newFile
 }
 
+// AddStreamFile adds a file whose size is not known in advance, such as a source read
+// from an io.Reader. The positions of maxSize bytes are reserved for it like AddFile
+// does, the file starts empty and grows with File.Grow. While it is the last file of
+// the set, File.Reserve extends it past maxSize; files added later start after the
+// positions reserved at that time, which then limit the size of the file.
+func (s *FileSet) AddStreamFile(filename string, base, maxSize int) *File {
+	f := s.AddFile(filename, base, maxSize)
+	f.size = 0
+	f.set = s
+	return f
+}
+
 func (s *FileSet) Base() int {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
@@ -93,12 +105,7 @@ func (s *FileSet) PositionFor(p Pos, adjusted bool) (pos Position) {
 		return
 	}
 
-	return file.Position(p)
-}
-
-func (s *FileSet) Read(decode func(any) error) error {
-	//TODO
-	return nil
+	return file.PositionFor(p, adjusted)
 }
 
 func (s *FileSet) RemoveFile(file *File) {
@@ -109,8 +116,3 @@ func (s *FileSet) RemoveFile(file *File) {
 		s.files = append(s.files[:index], s.files[index+1:]...)
 	}
 }
-
-func (s *FileSet) Write(encode func(any) error) error {
-	//TODO
-	return nil
-}
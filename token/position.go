@@ -8,6 +8,8 @@ type Position struct {
 	Offset   int    // offset, starting at 0
 	Line     int    // line number, starting at 1
 	Column   int    // column number, starting at 1 (byte count)
+
+	CharColumn int // column number, starting at 1 (character count)
 }
 
 // Position describes an arbitrary source position including the file, line, and column location. A Position is valid if the line number is > 0.
@@ -0,0 +1,72 @@
+package token
+
+type serializedFile struct {
+	// fields correspond 1:1 to fields with same (lower-case) name in File
+	Name      string
+	Base      int
+	Size      int
+	Limit     int
+	Lines     []int
+	Infos     []columnInfo
+	MultiByte []multiByteChar
+}
+
+type serializedFileSet struct {
+	Base  int
+	Files []serializedFile
+}
+
+// Read calls decode to deserialize a file set into s; s must not be nil.
+// The files of s are replaced by the decoded ones.
+func (s *FileSet) Read(decode func(any) error) error {
+	var ss serializedFileSet
+	if err := decode(&ss); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.base = ss.Base
+	files := make([]*File, len(ss.Files))
+	for i, f := range ss.Files {
+		files[i] = &File{
+			filename:    f.Name,
+			base:        f.Base,
+			size:        f.Size,
+			limit:       f.Limit,
+			line_start:  f.Lines,
+			column_info: f.Infos,
+			multibyte:   f.MultiByte,
+		}
+	}
+	s.files = files
+	return nil
+}
+
+// Write calls encode to serialize the file set s, with any encoder such as the ones
+// of encoding/gob or encoding/json. The Pos values of s remain valid in a file set
+// read back with Read.
+func (s *FileSet) Write(encode func(any) error) error {
+	var ss serializedFileSet
+
+	s.mutex.RLock()
+	ss.Base = s.base
+	files := make([]serializedFile, len(s.files))
+	for i, f := range s.files {
+		f.mutex.Lock()
+		files[i] = serializedFile{
+			Name:      f.filename,
+			Base:      f.base,
+			Size:      f.size,
+			Limit:     f.limit,
+			Lines:     append([]int(nil), f.line_start...),
+			Infos:     append([]columnInfo(nil), f.column_info...),
+			MultiByte: append([]multiByteChar(nil), f.multibyte...),
+		}
+		f.mutex.Unlock()
+	}
+	ss.Files = files
+	s.mutex.RUnlock()
+
+	return encode(ss)
+}
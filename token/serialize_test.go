@@ -0,0 +1,53 @@
+package token
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+)
+
+func TestSerialization(t *testing.T) {
+	src := "entity é is\n--line tpl.j2:3\nend;\n"
+	fset := NewFileSet()
+	a := fset.AddFile("a.vhd", -1, len(src))
+	a.SetLinesForContent([]byte(src))
+	a.AddMultiByteChar(7, 2)
+	a.AddLineColumnInfo(29, "tpl.j2", 3, 0)
+	b := fset.AddStreamFile("b.vhd", -1, 100)
+	b.Grow(10)
+	b.AddLine(4)
+	positions := []Pos{a.Pos(0), a.Pos(8), a.Pos(30), b.Pos(5), b.Pos(10)}
+
+	codecs := []struct {
+		name   string
+		encode func(*bytes.Buffer) func(any) error
+		decode func(*bytes.Buffer) func(any) error
+	}{
+		{"gob", func(w *bytes.Buffer) func(any) error { return gob.NewEncoder(w).Encode }, func(r *bytes.Buffer) func(any) error { return gob.NewDecoder(r).Decode }},
+		{"json", func(w *bytes.Buffer) func(any) error { return json.NewEncoder(w).Encode }, func(r *bytes.Buffer) func(any) error { return json.NewDecoder(r).Decode }},
+	}
+	for _, codec := range codecs {
+		var buf bytes.Buffer
+		if err := fset.Write(codec.encode(&buf)); err != nil {
+			t.Fatalf("%s: %v", codec.name, err)
+		}
+		loaded := NewFileSet()
+		if err := loaded.Read(codec.decode(&buf)); err != nil {
+			t.Fatalf("%s: %v", codec.name, err)
+		}
+		if loaded.Base() != fset.Base() {
+			t.Errorf("%s: got base %d, want %d", codec.name, loaded.Base(), fset.Base())
+		}
+		for _, p := range positions {
+			for _, adjusted := range []bool{false, true} {
+				if got, want := loaded.PositionFor(p, adjusted), fset.PositionFor(p, adjusted); got != want {
+					t.Errorf("%s: Pos %d: got %v, want %v", codec.name, p, got, want)
+				}
+			}
+		}
+		if f := loaded.File(b.Pos(0)); f == nil || f.MaxSize() != 100 || f.Size() != 10 {
+			t.Errorf("%s: got file %v, want b.vhd of size 10 and max size 100", codec.name, f)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package token
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Content returns the source content kept with SetContent, or nil.
+func (f *File) Content() []byte {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.content
+}
+
+// Text returns the source text in [start, end), it is empty if the file has no content.
+func (f *File) Text(start, end Pos) string {
+	content := f.Content()
+	if content == nil {
+		return ""
+	}
+	offs, endOffs := f.Offset(start), f.Offset(end)
+	if endOffs < offs {
+		return ""
+	}
+	return string(content[offs:endOffs])
+}
+
+// LineText returns the text of line without its end of line, it is empty if the file
+// has no content or no such line.
+func (f *File) LineText(line int) string {
+	content := f.Content()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if content == nil || line < 1 || line > len(f.line_start) {
+		return ""
+	}
+	end := f.size
+	if line < len(f.line_start) {
+		end = f.line_start[line]
+	}
+	text := string(content[f.line_start[line-1]:end])
+	return strings.TrimSuffix(strings.TrimSuffix(text, "\n"), "\r")
+}
+
+// CodeFrame renders the lines of [start, end) with context lines around them, the
+// range is underlined with carets. An empty range gets a single caret.
+//
+//	2 | entity E is
+//	3 |   port (a : bitt);
+//	  |             ^^^^
+//	4 | end;
+//
+// It is empty if the file has no content.
+func (f *File) CodeFrame(start, end Pos, context int) string {
+	if f.Content() == nil {
+		return ""
+	}
+	if end < start {
+		end = start
+	}
+	first, startColumn := f.LineColumn(start, ByteColumn)
+	last, endColumn := f.LineColumn(end, ByteColumn)
+	if first == 0 {
+		return ""
+	}
+	from, to := max(first-context, 1), min(last+context, f.LineCount())
+	width := len(fmt.Sprint(to))
+
+	var sb strings.Builder
+	for line := from; line <= to; line++ {
+		text := f.LineText(line)
+		fmt.Fprintf(&sb, "%*d | %s\n", width, line, text)
+		if line < first || line > last {
+			continue
+		}
+		//Byte columns of the underlined part of the line
+		left, right := 1, len(text)+1
+		if line == first {
+			left = startColumn
+		}
+		if line == last {
+			right = endColumn
+		}
+		right = min(right, len(text)+1)
+		if right <= left {
+			if start != end && line != first {
+				continue
+			}
+			right = left + 1
+		}
+		fmt.Fprintf(&sb, "%*s | %s%s\n", width, "", indent(text[:min(left-1, len(text))]), strings.Repeat("^", carets(text, left, right)))
+	}
+	return sb.String()
+}
+
+// indent returns blanks as wide as prefix, tabs are kept so the carets line up.
+func indent(prefix string) string {
+	var sb strings.Builder
+	for _, r := range prefix {
+		if r == '\t' {
+			sb.WriteRune('\t')
+		} else {
+			sb.WriteRune(' ')
+		}
+	}
+	return sb.String()
+}
+
+// carets returns the number of characters between the byte columns left and right of text,
+// a range at the end of the line gets one caret.
+func carets(text string, left, right int) int {
+	if left > len(text) {
+		return 1
+	}
+	return max(utf8.RuneCountInString(text[left-1:right-1]), 1)
+}
@@ -0,0 +1,60 @@
+package token
+
+// Standard is a revision of IEEE Std 1076, the zero value is the latest one.
+type Standard int
+
+const (
+	VHDL2019 Standard = iota
+	VHDL2008
+	VHDL2002
+	VHDL93
+	VHDL87
+)
+
+var standards = [...]string{
+	VHDL87:   "VHDL-87",
+	VHDL93:   "VHDL-93",
+	VHDL2002: "VHDL-2002",
+	VHDL2008: "VHDL-2008",
+	VHDL2019: "VHDL-2019",
+}
+
+func (std Standard) String() string {
+	if 0 <= std && int(std) < len(standards) {
+		return standards[std]
+	}
+	return "VHDL-?"
+}
+
+// Includes reports whether a construct introduced by the since revision is part of std.
+func (std Standard) Includes(since Standard) bool {
+	//Newer revisions have lower values
+	return std <= since
+}
+
+// Since returns the revision of the standard that introduced tok as a reserved word or delimiter.
+func (tok Token) Since() Standard {
+	switch tok {
+	case GROUP, IMPURE, INERTIAL, LITERAL, POSTPONED, PURE, REJECT, ROL, ROR, SHARED,
+		SLA, SLL, SRA, SRL, UNAFFECTED, XNOR, LSQPAREN, RSQPAREN:
+		return VHDL93
+	case PROTECTED:
+		return VHDL2002
+	case ASSUME, CONTEXT, COVER, DEFAULT, FAIRNESS, FORCE, PARAMETER, PROPERTY, RELEASE,
+		RESTRICT, SEQUENCE, STRONG, VMODE, VPROP, VUNIT,
+		QUEST, AT, BACKTICK, PROTECT, PROTECT_DATA, COND_CONV, MEQ, MNEQ, MLTH, MLEQ, MGTH, MGEQ, DOUBLE_LTH, DOUBLE_GTH:
+		return VHDL2008
+	case PRIVATE, VIEW, VPKG:
+		return VHDL2019
+	}
+	return VHDL87
+}
+
+// LookupStandard is like Lookup but only the reserved words of std are keywords,
+// e.g. "context" is an identifier in VHDL-93.
+func LookupStandard(ident string, std Standard) Token {
+	if tok := Lookup(ident); tok == IDENT || std.Includes(tok.Since()) {
+		return tok
+	}
+	return IDENT
+}
@@ -1,5 +1,7 @@
 package token
 
+import "strings"
+
 type Token rune
 
 const (
@@ -16,6 +18,10 @@ const (
 	STRING  // "abc"
 	BIT_STR // B"1111_1111_1111"
 	COMMENT // --single /*multiple*/
+	PRAGMA  // -- synthesis translate_off
+
+	PROTECT      // `protect begin_protected
+	PROTECT_DATA // encoded lines of a `protect data_block
 	literal_end
 
 	operator_beg
@@ -189,13 +195,22 @@ const (
 )
 
 var tokens = [...]string{
+	ILLEGAL: "ILLEGAL",
+	EOF:     "EOF",
+
 	IDENT:   "IDENT",   // main_main
 	DEC:     "DEC",     // 6.023E+24
+	INT:     "INT",     // 4563
+	REAL:    "REAL",    // 4.563
 	BASED:   "BASED",   // 2#1111_1111# -> 255
 	CHAR:    "CHAR",    // 'a'
 	STRING:  "STRING",  // "abc"
 	BIT_STR: "BIT_STR", // B"1111_1111_1111"
 	COMMENT: "COMMENT", // --single /*multiple*/
+	PRAGMA:  "PRAGMA",  // -- synthesis translate_off
+
+	PROTECT:      "PROTECT",      // `protect begin_protected
+	PROTECT_DATA: "PROTECT_DATA", // encoded lines of a `protect data_block
 
 	CONCAT:     "&",   // &
 	APOS:       "'",   // '
@@ -359,22 +374,65 @@ var keywords map[string]Token
 func init() {
 	keywords_size := (keyword_end - keyword_beg + 1) + (oper_key_end - oper_key_beg + 1)
 	keywords = make(map[string]Token, keywords_size)
+	//Keywords are stored normalized so the lookup follows the identifier case rules
 	for i := oper_key_beg + 1; i < oper_key_end; i++ {
-		keywords[tokens[i]] = i
+		keywords[strings.ToLower(tokens[i])] = i
 	}
 	for i := keyword_beg + 1; i < keyword_end; i++ {
-		keywords[tokens[i]] = i
+		keywords[strings.ToLower(tokens[i])] = i
 	}
 }
 
+// Lookup maps an identifier to its keyword token or IDENT (if not a keyword).
+// Reserved words are case insensitive, "entity", "Entity" and "ENTITY" are all ENTITY.
 func Lookup(ident string) Token {
-	tok, is_keyword := keywords[ident]
+	tok, is_keyword := keywords[NormalizeIdent(ident)]
 	if !is_keyword {
 		return IDENT
 	}
 	return tok
 }
 
+// NormalizeIdent returns the form of an identifier used for comparisons.
+// LRM 15.4.2: basic identifiers differing only in the use of corresponding upper
+// and lower case letters are the same, so every upper case letter of ISO/IEC 8859-1
+// is mapped to its lower case counterpart. Letters without a lower case counterpart
+// and any other character are kept as they are.
+// LRM 15.4.3: extended identifiers are case sensitive and returned unchanged.
+func NormalizeIdent(ident string) string {
+	if IsExtendedIdent(ident) {
+		return ident
+	}
+	for i := 0; i < len(ident); i++ {
+		if ch := ident[i]; 'A' <= ch && ch <= 'Z' || ch >= 0x80 {
+			return strings.Map(lowerLatin1, ident)
+		}
+	}
+	//Common case, nothing to map
+	return ident
+}
+
+func lowerLatin1(ch rune) rune {
+	switch {
+	case 'A' <= ch && ch <= 'Z':
+		return ch + ('a' - 'A')
+	case 0xC0 <= ch && ch <= 0xDE && ch != 0xD7: // À..Þ except ×
+		return ch + 0x20
+	}
+	return ch
+}
+
+// IsExtendedIdent reports whether ident is an extended identifier such as \bus[3]\.
+func IsExtendedIdent(ident string) bool {
+	return len(ident) > 0 && ident[0] == '\\'
+}
+
+// EqualIdent reports whether two identifiers denote the same identifier, e.g. an
+// end label and the name of the declaration it closes.
+func EqualIdent(a, b string) bool {
+	return a == b || NormalizeIdent(a) == NormalizeIdent(b)
+}
+
 func (tok Token) IsKeyword() bool {
 	return oper_key_beg < tok && tok < oper_key_end || keyword_beg < tok && tok < keyword_end
 }
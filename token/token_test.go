@@ -0,0 +1,43 @@
+package token
+
+import "testing"
+
+func TestLookupCaseInsensitive(t *testing.T) {
+	tests := []struct {
+		ident string
+		tok   Token
+	}{
+		{"entity", ENTITY},
+		{"Entity", ENTITY},
+		{"ENTITY", ENTITY},
+		{"eNtItY", ENTITY},
+		{"xnor", XNOR},
+		{"Downto", DOWNTO},
+		{"entity_1", IDENT},
+		{"clk", IDENT},
+	}
+	for _, test := range tests {
+		if tok := Lookup(test.ident); tok != test.tok {
+			t.Errorf("Lookup(%q) = %s, want %s", test.ident, tok, test.tok)
+		}
+	}
+}
+
+func TestEqualIdent(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		equal bool
+	}{
+		{"uart", "UART", true},
+		{"Data_In", "data_in", true},
+		{"data_in", "data_out", false},
+		{"Ångström", "åNGSTRÖM", true},
+		{"straße", "STRASSE", false},
+		{"x×y", "X×Y", true},
+	}
+	for _, test := range tests {
+		if equal := EqualIdent(test.a, test.b); equal != test.equal {
+			t.Errorf("EqualIdent(%q, %q) = %t, want %t", test.a, test.b, equal, test.equal)
+		}
+	}
+}